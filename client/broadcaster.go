@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client/grpc"
+	"github.com/b-harvest/cosmos-module-stress-test/client/lcd"
+	"github.com/b-harvest/cosmos-module-stress-test/client/rpc"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+const (
+	// TransportGRPC broadcasts through the gRPC tx service.
+	TransportGRPC = "grpc"
+	// TransportRPC broadcasts through broadcast_tx_async, broadcast_tx_sync and broadcast_tx_commit of Tendermint RPC.
+	TransportRPC = "rpc"
+	// TransportLCD broadcasts through /cosmos/tx/v1beta1/txs of the REST server.
+	TransportLCD = "lcd"
+)
+
+// Broadcaster broadcasts transactions to a node over one of its transports.
+type Broadcaster interface {
+	Broadcast(ctx context.Context, txBytes []byte, mode sdktx.BroadcastMode) (*sdktypes.TxResponse, error)
+}
+
+var (
+	_ Broadcaster = (*grpc.Client)(nil)
+	_ Broadcaster = (*rpc.Client)(nil)
+	_ Broadcaster = (*lcd.Client)(nil)
+)
+
+// NewBroadcaster returns the broadcaster of the transport. The gRPC transport is the default.
+// The REST server client is connected only for the LCD transport.
+func (c *Client) NewBroadcaster(transport string, lcdURL string) (Broadcaster, error) {
+	switch transport {
+	case "", TransportGRPC:
+		return c.GRPC, nil
+	case TransportRPC:
+		return c.RPC, nil
+	case TransportLCD:
+		return lcd.NewClient(lcdURL, DefaultLCDTimeout, DefaultCommitTimeout)
+	default:
+		return nil, fmt.Errorf("unsupported transport %s; must be one of %s, %s or %s", transport, TransportGRPC, TransportRPC, TransportLCD)
+	}
+}
@@ -20,7 +20,7 @@ var (
 func TestMain(m *testing.M) {
 	codec.SetCodec()
 
-	rpcClient, _ := rpc.NewClient(rpcAddress, 5)
+	rpcClient, _ := rpc.NewClient(rpcAddress, 5, 60)
 
 	c = clictx.NewClient(rpcAddress, rpcClient)
 
@@ -14,6 +14,11 @@ import (
 var (
 	DefaultRPCTimeout  = int64(5)
 	DefaultGRPCTimeout = int64(5)
+	DefaultLCDTimeout  = int64(5)
+
+	// DefaultCommitTimeout is the timeout in seconds of a broadcast in the block mode, which waits for the
+	// transaction to be committed. It outlasts the timeout_broadcast_tx_commit of the node, 10s by default.
+	DefaultCommitTimeout = int64(60)
 )
 
 // Client is a wrapper for various clients.
@@ -29,12 +34,12 @@ func NewClient(rpcURL string, grpcURL string) (*Client, error) {
 
 	log.Debug().Msg("connecting clients")
 
-	rpcClient, err := rpc.NewClient(rpcURL, DefaultRPCTimeout)
+	rpcClient, err := rpc.NewClient(rpcURL, DefaultRPCTimeout, DefaultCommitTimeout)
 	if err != nil {
 		return &Client{}, err
 	}
 
-	grpcClient, err := grpc.NewClient(grpcURL, DefaultGRPCTimeout)
+	grpcClient, err := grpc.NewClient(grpcURL, DefaultGRPCTimeout, DefaultCommitTimeout)
 	if err != nil {
 		return &Client{}, err
 	}
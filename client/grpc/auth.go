@@ -32,3 +32,15 @@ func (c *Client) GetBaseAccountInfo(ctx context.Context, address string) (authty
 
 	return acc, nil
 }
+
+// GetAuthParams returns the parameters of the auth module.
+func (c *Client) GetAuthParams(ctx context.Context) (authtypes.Params, error) {
+	client := c.GetAuthQueryClient()
+
+	resp, err := client.Params(ctx, &authtypes.QueryParamsRequest{})
+	if err != nil {
+		return authtypes.Params{}, err
+	}
+
+	return resp.Params, nil
+}
@@ -15,3 +15,10 @@ func TestBaseAccountInfo(t *testing.T) {
 	t.Log(resp.GetAccountNumber())
 	t.Log(resp.GetSequence())
 }
+
+func TestGetAuthParams(t *testing.T) {
+	params, err := c.GetAuthParams(context.Background())
+	require.NoError(t, err)
+
+	t.Log(params.MaxMemoCharacters)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkquery "github.com/cosmos/cosmos-sdk/types/query"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 )
 
@@ -28,3 +29,34 @@ func (c *Client) GetBalance(ctx context.Context, address string, denom string) (
 
 	return resp.GetBalance(), nil
 }
+
+// GetAllBalances returns all balances of a given account.
+func (c *Client) GetAllBalances(ctx context.Context, address string) (sdktypes.Coins, error) {
+	bankClient := c.GetBankQueryClient()
+
+	balances := sdktypes.NewCoins()
+
+	var nextKey []byte
+	for {
+		req := banktypes.QueryAllBalancesRequest{
+			Address: address,
+			Pagination: &sdkquery.PageRequest{
+				Key: nextKey,
+			},
+		}
+
+		resp, err := bankClient.AllBalances(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+
+		balances = balances.Add(resp.GetBalances()...)
+
+		nextKey = resp.GetPagination().GetNextKey()
+		if len(nextKey) == 0 {
+			break
+		}
+	}
+
+	return balances, nil
+}
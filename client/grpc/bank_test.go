@@ -0,0 +1,16 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAllBalances(t *testing.T) {
+	address := "cosmos1zaavvzxez0elundtn32qnk9lkm8kmcszzsv80v"
+	balances, err := c.GetAllBalances(context.Background(), address)
+	require.NoError(t, err)
+
+	t.Log(balances)
+}
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -12,10 +13,13 @@ import (
 // Client wraps GRPC client connection.
 type Client struct {
 	*grpc.ClientConn
+	timeout       time.Duration // deadline of a broadcast
+	commitTimeout time.Duration // deadline of a broadcast in the block mode
 }
 
-// NewClient creates GRPC client.
-func NewClient(grpcURL string, timeout int64) (*Client, error) {
+// NewClient creates GRPC client whose broadcasts time out after the timeout in seconds,
+// or after the commit timeout in seconds in the block mode.
+func NewClient(grpcURL string, timeout int64, commitTimeout int64) (*Client, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -24,7 +28,11 @@ func NewClient(grpcURL string, timeout int64) (*Client, error) {
 		return &Client{}, fmt.Errorf("failed to connect GRPC client: %s", err)
 	}
 
-	return &Client{client}, nil
+	return &Client{
+		ClientConn:    client,
+		timeout:       time.Duration(timeout) * time.Second,
+		commitTimeout: time.Duration(commitTimeout) * time.Second,
+	}, nil
 }
 
 // IsNotFound returns not found status.
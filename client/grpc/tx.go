@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 )
 
@@ -13,12 +14,52 @@ func (c *Client) GetTxClient() tx.ServiceClient {
 
 // BroadcastTx broadcasts transaction.
 func (c *Client) BroadcastTx(ctx context.Context, txBytes []byte) (*tx.BroadcastTxResponse, error) {
+	return c.BroadcastTxMode(ctx, txBytes, tx.BroadcastMode_BROADCAST_MODE_ASYNC) // should use async mode for the stress testing
+}
+
+// BroadcastTxMode broadcasts transaction in the given broadcast mode.
+// Unlike the async mode, the sync mode returns the CheckTx result of the transaction.
+// The broadcast fails with DeadlineExceeded when the node does not respond within the timeout of the client,
+// or within its commit timeout in the block mode, which waits for the transaction to be committed.
+func (c *Client) BroadcastTxMode(ctx context.Context, txBytes []byte, mode tx.BroadcastMode) (*tx.BroadcastTxResponse, error) {
 	client := c.GetTxClient()
 
+	timeout := c.timeout
+	if mode == tx.BroadcastMode_BROADCAST_MODE_BLOCK {
+		timeout = c.commitTimeout
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	req := &tx.BroadcastTxRequest{
 		TxBytes: txBytes,
-		Mode:    tx.BroadcastMode_BROADCAST_MODE_ASYNC, // should use async mode for the stress testing
+		Mode:    mode,
 	}
 
 	return client.BroadcastTx(ctx, req)
 }
+
+// Broadcast broadcasts transaction in the given broadcast mode and returns its response.
+func (c *Client) Broadcast(ctx context.Context, txBytes []byte, mode tx.BroadcastMode) (*sdktypes.TxResponse, error) {
+	resp, err := c.BroadcastTxMode(ctx, txBytes, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.TxResponse, nil
+}
+
+// Simulate simulates transaction and returns the gas used by its execution.
+func (c *Client) Simulate(ctx context.Context, protoTx *tx.Tx) (*tx.SimulateResponse, error) {
+	client := c.GetTxClient()
+
+	req := &tx.SimulateRequest{
+		Tx: protoTx,
+	}
+
+	return client.Simulate(ctx, req)
+}
@@ -0,0 +1,46 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client/grpc"
+
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stuckTxServer is a tx service whose broadcasts never return, like a node that hangs.
+type stuckTxServer struct {
+	sdktx.UnimplementedServiceServer
+}
+
+func (s *stuckTxServer) BroadcastTx(ctx context.Context, req *sdktx.BroadcastTxRequest) (*sdktx.BroadcastTxResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBroadcastTxModeTimeout(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpcgo.NewServer()
+	sdktx.RegisterServiceServer(server, &stuckTxServer{})
+	go server.Serve(lis) // nolint: errcheck
+	defer server.Stop()
+
+	client, err := grpc.NewClient(lis.Addr().String(), 1, 60)
+	require.NoError(t, err)
+	defer client.Close() // nolint: errcheck
+
+	start := time.Now()
+	_, err = client.BroadcastTxMode(context.Background(), []byte("tx"), sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.True(t, time.Since(start) < 3*time.Second)
+}
@@ -0,0 +1,31 @@
+package lcd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client wraps HTTP client of the REST server.
+type Client struct {
+	*http.Client
+	address       string
+	commitTimeout time.Duration // deadline of a broadcast in the block mode
+}
+
+// NewClient creates REST server client whose requests time out after the timeout in seconds,
+// except the broadcasts in the block mode, which time out after the commit timeout in seconds.
+func NewClient(lcdURL string, timeout int64, commitTimeout int64) (*Client, error) {
+	u, err := url.Parse(lcdURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return &Client{}, fmt.Errorf("failed to parse REST server address %s", lcdURL)
+	}
+
+	return &Client{
+		Client:        &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		address:       strings.TrimSuffix(lcdURL, "/"),
+		commitTimeout: time.Duration(commitTimeout) * time.Second,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package lcd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/b-harvest/cosmos-module-stress-test/codec"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// Broadcast broadcasts transaction through the /cosmos/tx/v1beta1/txs endpoint in the given broadcast mode
+// and returns its response.
+func (c *Client) Broadcast(ctx context.Context, txBytes []byte, mode tx.BroadcastMode) (*sdktypes.TxResponse, error) {
+	body, err := codec.AppCodec.MarshalJSON(&tx.BroadcastTxRequest{
+		TxBytes: txBytes,
+		Mode:    mode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal broadcast request: %s", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.address+"/cosmos/tx/v1beta1/txs", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.Client
+	if mode == tx.BroadcastMode_BROADCAST_MODE_BLOCK {
+		// the block mode waits for the transaction to be committed, which takes longer than the timeout of the client
+		commitClient := *c.Client
+		commitClient.Timeout = c.commitTimeout
+		client = &commitClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read broadcast response: %s", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to broadcast transaction: %s: %s", resp.Status, respBody)
+	}
+
+	var broadcastResp tx.BroadcastTxResponse
+	err = codec.AppCodec.UnmarshalJSON(respBody, &broadcastResp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal broadcast response: %s", err)
+	}
+
+	return broadcastResp.TxResponse, nil
+}
@@ -0,0 +1,100 @@
+package lcd_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client/lcd"
+	"github.com/b-harvest/cosmos-module-stress-test/codec"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+func TestBroadcast(t *testing.T) {
+	codec.SetCodec()
+
+	txBytes := []byte("tx bytes")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/cosmos/tx/v1beta1/txs", r.URL.Path)
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req tx.BroadcastTxRequest
+		require.NoError(t, codec.AppCodec.UnmarshalJSON(body, &req))
+		require.Equal(t, txBytes, req.TxBytes)
+		require.Equal(t, tx.BroadcastMode_BROADCAST_MODE_SYNC, req.Mode)
+
+		resp, err := codec.AppCodec.MarshalJSON(&tx.BroadcastTxResponse{
+			TxResponse: &sdktypes.TxResponse{TxHash: "ABCD", Code: 5, Codespace: "sdk", RawLog: "insufficient funds"},
+		})
+		require.NoError(t, err)
+
+		_, err = w.Write(resp)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	c, err := lcd.NewClient(server.URL+"/", 5, 60)
+	require.NoError(t, err)
+
+	resp, err := c.Broadcast(context.Background(), txBytes, tx.BroadcastMode_BROADCAST_MODE_SYNC)
+	require.NoError(t, err)
+	require.Equal(t, "ABCD", resp.TxHash)
+	require.Equal(t, uint32(5), resp.Code)
+	require.Equal(t, "insufficient funds", resp.RawLog)
+
+	_, err = lcd.NewClient("localhost:1317", 5, 60)
+	require.Error(t, err)
+}
+
+func TestBroadcastError(t *testing.T) {
+	codec.SetCodec()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code":3,"message":"invalid tx bytes"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c, err := lcd.NewClient(server.URL, 5, 60)
+	require.NoError(t, err)
+
+	_, err = c.Broadcast(context.Background(), []byte("tx bytes"), tx.BroadcastMode_BROADCAST_MODE_SYNC)
+	require.Error(t, err)
+}
+
+func TestBroadcastCommitTimeout(t *testing.T) {
+	codec.SetCodec()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond) // longer than the timeout of the client, like a block to commit
+
+		resp, err := codec.AppCodec.MarshalJSON(&tx.BroadcastTxResponse{
+			TxResponse: &sdktypes.TxResponse{TxHash: "ABCD", Height: 10},
+		})
+		require.NoError(t, err)
+
+		_, err = w.Write(resp)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	c, err := lcd.NewClient(server.URL, 1, 60)
+	require.NoError(t, err)
+
+	_, err = c.Broadcast(context.Background(), []byte("tx bytes"), tx.BroadcastMode_BROADCAST_MODE_SYNC)
+	require.Error(t, err)
+
+	resp, err := c.Broadcast(context.Background(), []byte("tx bytes"), tx.BroadcastMode_BROADCAST_MODE_BLOCK)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), resp.Height)
+}
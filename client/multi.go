@@ -0,0 +1,274 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client/grpc"
+	"github.com/b-harvest/cosmos-module-stress-test/client/lcd"
+	"github.com/b-harvest/cosmos-module-stress-test/client/rpc"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// BroadcastPolicy selects the endpoints a transaction is broadcast to.
+type BroadcastPolicy string
+
+const (
+	// PolicyRoundRobin broadcasts every transaction to the next endpoint in turn. The consecutive transactions of
+	// an account reach different nodes, which may receive them out of order and reject them with a wrong sequence.
+	PolicyRoundRobin BroadcastPolicy = "round-robin"
+	// PolicyRandom broadcasts every transaction to an endpoint picked at random.
+	PolicyRandom BroadcastPolicy = "random"
+	// PolicySticky broadcasts the transactions of an account to the same endpoint, so that they reach it in order.
+	// It is the default policy.
+	PolicySticky BroadcastPolicy = "sticky"
+	// PolicyAll broadcasts every transaction to every endpoint.
+	PolicyAll BroadcastPolicy = "all"
+)
+
+// ParseBroadcastPolicy parses the broadcast policy of the config. The sticky policy is the default.
+func ParseBroadcastPolicy(policy string) (BroadcastPolicy, error) {
+	switch BroadcastPolicy(policy) {
+	case "":
+		return PolicySticky, nil
+	case PolicyRoundRobin, PolicyRandom, PolicySticky, PolicyAll:
+		return BroadcastPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unsupported broadcast policy %s; must be one of %s, %s, %s or %s", policy, PolicyRoundRobin, PolicyRandom, PolicySticky, PolicyAll)
+	}
+}
+
+// Endpoint is a node that transactions are broadcast to.
+// The RPC client is used to query the mempool of the node and is nil when the node has no RPC address.
+type Endpoint struct {
+	Name        string
+	Broadcaster Broadcaster
+	RPC         *rpc.Client
+}
+
+// DialEndpoint connects to the node of the addresses over the transport.
+// The address of the transport must be set, and the RPC address is used to query the mempool as well.
+func DialEndpoint(transport string, rpcURL string, grpcURL string, lcdURL string) (Endpoint, error) {
+	var endpoint Endpoint
+
+	if rpcURL != "" {
+		rpcClient, err := rpc.NewClient(rpcURL, DefaultRPCTimeout, DefaultCommitTimeout)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		endpoint.RPC = rpcClient
+	}
+
+	switch transport {
+	case "", TransportGRPC:
+		if grpcURL == "" {
+			return Endpoint{}, fmt.Errorf("grpc address of the endpoint must be set to broadcast over %s", TransportGRPC)
+		}
+
+		grpcClient, err := grpc.NewClient(grpcURL, DefaultGRPCTimeout, DefaultCommitTimeout)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		endpoint.Name = grpcURL
+		endpoint.Broadcaster = grpcClient
+	case TransportRPC:
+		if endpoint.RPC == nil {
+			return Endpoint{}, fmt.Errorf("rpc address of the endpoint must be set to broadcast over %s", TransportRPC)
+		}
+		endpoint.Name = rpcURL
+		endpoint.Broadcaster = endpoint.RPC
+	case TransportLCD:
+		if lcdURL == "" {
+			return Endpoint{}, fmt.Errorf("lcd address of the endpoint must be set to broadcast over %s", TransportLCD)
+		}
+
+		lcdClient, err := lcd.NewClient(lcdURL, DefaultLCDTimeout, DefaultCommitTimeout)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		endpoint.Name = lcdURL
+		endpoint.Broadcaster = lcdClient
+	default:
+		return Endpoint{}, fmt.Errorf("unsupported transport %s; must be one of %s, %s or %s", transport, TransportGRPC, TransportRPC, TransportLCD)
+	}
+
+	return endpoint, nil
+}
+
+// EndpointStats counts the broadcast results and the latencies of an endpoint.
+type EndpointStats struct {
+	Name     string
+	Sent     uint64 // broadcast with a response
+	Failed   uint64 // failed to be broadcast
+	Rejected uint64 // broadcast with a non-zero code
+
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// AvgLatency returns the average latency of the broadcasts to the endpoint.
+func (s EndpointStats) AvgLatency() time.Duration {
+	count := s.Sent + s.Failed
+	if count == 0 {
+		return 0
+	}
+
+	return s.TotalLatency / time.Duration(count)
+}
+
+// MultiBroadcaster distributes transactions over several endpoints by the broadcast policy
+// and records the broadcast results of every endpoint.
+type MultiBroadcaster struct {
+	policy    BroadcastPolicy
+	endpoints []Endpoint
+
+	mu     sync.Mutex
+	next   int
+	owners map[string]int
+	stats  []EndpointStats
+}
+
+// NewMultiBroadcaster returns new MultiBroadcaster object.
+func NewMultiBroadcaster(policy BroadcastPolicy, endpoints []Endpoint) (*MultiBroadcaster, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint must be given")
+	}
+
+	stats := make([]EndpointStats, len(endpoints))
+	for i, endpoint := range endpoints {
+		stats[i].Name = endpoint.Name
+	}
+
+	return &MultiBroadcaster{
+		policy:    policy,
+		endpoints: endpoints,
+		owners:    make(map[string]int),
+		stats:     stats,
+	}, nil
+}
+
+// Broadcast broadcasts the transaction of the account to the endpoints picked by the policy.
+// When the transaction is broadcast to every endpoint, an accepted response is preferred over a rejected one,
+// since the other nodes may reject it as a duplicate once it is gossiped.
+func (m *MultiBroadcaster) Broadcast(ctx context.Context, address string, txBytes []byte, mode sdktx.BroadcastMode) (*sdktypes.TxResponse, error) {
+	if m.policy != PolicyAll {
+		return m.broadcast(ctx, m.pick(address), txBytes, mode)
+	}
+
+	resps := make([]*sdktypes.TxResponse, len(m.endpoints))
+	errs := make([]error, len(m.endpoints))
+
+	var wg sync.WaitGroup
+	for i := range m.endpoints {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resps[i], errs[i] = m.broadcast(ctx, i, txBytes, mode)
+		}(i)
+	}
+	wg.Wait()
+
+	var resp *sdktypes.TxResponse
+	for i := range m.endpoints {
+		if errs[i] != nil {
+			continue
+		}
+
+		if resp == nil || (resp.Code != 0 && resps[i].Code == 0) {
+			resp = resps[i]
+		}
+	}
+
+	if resp == nil {
+		return nil, errs[0]
+	}
+
+	return resp, nil
+}
+
+// pick returns the index of the endpoint the transaction of the account is broadcast to.
+func (m *MultiBroadcaster) pick(address string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch m.policy {
+	case PolicyRandom:
+		return rand.Intn(len(m.endpoints))
+	case PolicySticky:
+		owner, ok := m.owners[address]
+		if !ok {
+			owner = len(m.owners) % len(m.endpoints)
+			m.owners[address] = owner
+		}
+		return owner
+	default:
+		i := m.next
+		m.next = (m.next + 1) % len(m.endpoints)
+		return i
+	}
+}
+
+// broadcast broadcasts the transaction to the endpoint of the index and records the result.
+func (m *MultiBroadcaster) broadcast(ctx context.Context, i int, txBytes []byte, mode sdktx.BroadcastMode) (*sdktypes.TxResponse, error) {
+	start := time.Now()
+	resp, err := m.endpoints[i].Broadcaster.Broadcast(ctx, txBytes, mode)
+	latency := time.Since(start)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := &m.stats[i]
+	stats.TotalLatency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+
+	switch {
+	case err != nil:
+		stats.Failed++
+		// the error is wrapped rather than formatted so that its class can be told
+		return nil, fmt.Errorf("%s: %w", m.endpoints[i].Name, err)
+	case resp.Code != 0:
+		stats.Sent++
+		stats.Rejected++
+	default:
+		stats.Sent++
+	}
+
+	return resp, nil
+}
+
+// Endpoints returns the endpoints of the broadcaster.
+func (m *MultiBroadcaster) Endpoints() []Endpoint {
+	return m.endpoints
+}
+
+// Stats returns the broadcast results of every endpoint.
+func (m *MultiBroadcaster) Stats() []EndpointStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]EndpointStats, len(m.stats))
+	copy(stats, m.stats)
+
+	return stats
+}
+
+// Close closes the connections of the endpoints that hold one.
+func (m *MultiBroadcaster) Close() error {
+	for _, endpoint := range m.endpoints {
+		if closer, ok := endpoint.Broadcaster.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// fakeBroadcaster records the transactions broadcast to it and responds with the code.
+type fakeBroadcaster struct {
+	mu   sync.Mutex
+	txs  []string
+	code uint32
+	err  error
+}
+
+func (b *fakeBroadcaster) Broadcast(ctx context.Context, txBytes []byte, mode sdktx.BroadcastMode) (*sdktypes.TxResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.txs = append(b.txs, string(txBytes))
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return &sdktypes.TxResponse{Code: b.code}, nil
+}
+
+func newEndpoints(broadcasters ...*fakeBroadcaster) []client.Endpoint {
+	endpoints := make([]client.Endpoint, 0, len(broadcasters))
+	for i, b := range broadcasters {
+		endpoints = append(endpoints, client.Endpoint{Name: fmt.Sprintf("node%d", i), Broadcaster: b})
+	}
+
+	return endpoints
+}
+
+func TestParseBroadcastPolicy(t *testing.T) {
+	policy, err := client.ParseBroadcastPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, client.PolicySticky, policy)
+
+	for _, p := range []string{"round-robin", "random", "sticky", "all"} {
+		policy, err := client.ParseBroadcastPolicy(p)
+		require.NoError(t, err)
+		require.Equal(t, client.BroadcastPolicy(p), policy)
+	}
+
+	_, err = client.ParseBroadcastPolicy("least-loaded")
+	require.Error(t, err)
+}
+
+func TestMultiBroadcasterRoundRobin(t *testing.T) {
+	a, b := &fakeBroadcaster{}, &fakeBroadcaster{}
+
+	m, err := client.NewMultiBroadcaster(client.PolicyRoundRobin, newEndpoints(a, b))
+	require.NoError(t, err)
+
+	for _, tx := range []string{"tx1", "tx2", "tx3"} {
+		_, err := m.Broadcast(context.Background(), "addr", []byte(tx), sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"tx1", "tx3"}, a.txs)
+	require.Equal(t, []string{"tx2"}, b.txs)
+
+	stats := m.Stats()
+	require.Equal(t, "node0", stats[0].Name)
+	require.Equal(t, uint64(2), stats[0].Sent)
+	require.Equal(t, uint64(1), stats[1].Sent)
+}
+
+func TestMultiBroadcasterSticky(t *testing.T) {
+	a, b := &fakeBroadcaster{}, &fakeBroadcaster{}
+
+	m, err := client.NewMultiBroadcaster(client.PolicySticky, newEndpoints(a, b))
+	require.NoError(t, err)
+
+	for _, tx := range []struct{ address, bytes string }{
+		{"addr1", "tx1"}, {"addr2", "tx2"}, {"addr1", "tx3"}, {"addr2", "tx4"},
+	} {
+		_, err := m.Broadcast(context.Background(), tx.address, []byte(tx.bytes), sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"tx1", "tx3"}, a.txs)
+	require.Equal(t, []string{"tx2", "tx4"}, b.txs)
+}
+
+func TestMultiBroadcasterAll(t *testing.T) {
+	// the node that received the transaction by gossip rejects it as a duplicate
+	a, b, c := &fakeBroadcaster{code: 19}, &fakeBroadcaster{}, &fakeBroadcaster{err: fmt.Errorf("connection refused")}
+
+	m, err := client.NewMultiBroadcaster(client.PolicyAll, newEndpoints(a, b, c))
+	require.NoError(t, err)
+
+	resp, err := m.Broadcast(context.Background(), "addr", []byte("tx1"), sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), resp.Code)
+
+	require.Equal(t, []string{"tx1"}, a.txs)
+	require.Equal(t, []string{"tx1"}, b.txs)
+	require.Equal(t, []string{"tx1"}, c.txs)
+
+	stats := m.Stats()
+	require.Equal(t, uint64(1), stats[0].Rejected)
+	require.Equal(t, uint64(1), stats[1].Sent)
+	require.Equal(t, uint64(0), stats[1].Rejected)
+	require.Equal(t, uint64(1), stats[2].Failed)
+
+	// every endpoint fails
+	m, err = client.NewMultiBroadcaster(client.PolicyAll, newEndpoints(c))
+	require.NoError(t, err)
+
+	_, err = m.Broadcast(context.Background(), "addr", []byte("tx2"), sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+	require.Error(t, err)
+
+	_, err = client.NewMultiBroadcaster(client.PolicyAll, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorClass is the class of a failed broadcast, which decides whether the broadcast is retried.
+type ErrorClass string
+
+const (
+	// ClassUnavailable is a broadcast to a node that cannot be reached, e.g. a gRPC Unavailable error.
+	ClassUnavailable ErrorClass = "unavailable"
+	// ClassDeadlineExceeded is a broadcast that timed out, e.g. a gRPC DeadlineExceeded error.
+	ClassDeadlineExceeded ErrorClass = "deadline-exceeded"
+	// ClassMempoolFull is a transaction rejected because the mempool of the node is full.
+	ClassMempoolFull ErrorClass = "mempool-full"
+	// ClassTxInCache is a transaction rejected because the mempool cache of the node already holds it, e.g. after
+	// a broadcast that timed out but reached the node. A retry may reach another endpoint that does not hold it.
+	ClassTxInCache ErrorClass = "tx-in-cache"
+	// ClassPermanent is a broadcast that failed for any other reason, which is not retried.
+	ClassPermanent ErrorClass = "permanent"
+)
+
+// Transient returns true when a broadcast failed with the class may succeed when it is retried.
+func (c ErrorClass) Transient() bool {
+	return c != ClassPermanent
+}
+
+// ClassifyBroadcast returns the class of the broadcast of the response and the error.
+// It returns false when the broadcast did not fail, which includes a transaction rejected for another reason
+// than a full mempool or the mempool cache, since the rejection is the result of the transaction itself.
+func ClassifyBroadcast(resp *sdktypes.TxResponse, err error) (ErrorClass, bool) {
+	if err != nil {
+		return classifyError(err), true
+	}
+
+	if resp == nil || resp.Codespace != sdkerrors.RootCodespace {
+		return "", false
+	}
+
+	switch resp.Code {
+	case sdkerrors.ErrMempoolIsFull.ABCICode():
+		return ClassMempoolFull, true
+	case sdkerrors.ErrTxInMempoolCache.ABCICode():
+		return ClassTxInCache, true
+	default:
+		return "", false
+	}
+}
+
+// classifyError returns the class of the error of a broadcast over any of the transports.
+func classifyError(err error) ErrorClass {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.GRPCStatus().Code() {
+		case codes.Unavailable:
+			return ClassUnavailable
+		case codes.DeadlineExceeded:
+			return ClassDeadlineExceeded
+		default:
+			return ClassPermanent
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ClassDeadlineExceeded
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ClassDeadlineExceeded
+		}
+		return ClassUnavailable
+	}
+
+	// a full mempool is returned as a plain error by some transports
+	if strings.Contains(err.Error(), "mempool is full") {
+		return ClassMempoolFull
+	}
+
+	return ClassPermanent
+}
+
+const (
+	// DefaultMaxRetries is the number of times a broadcast is retried when no limit is configured.
+	DefaultMaxRetries = 3
+	// DefaultRetryBaseDelay is the backoff before the first retry when no delay is configured.
+	DefaultRetryBaseDelay = 100 * time.Millisecond
+	// DefaultRetryMaxDelay is the longest backoff before a retry when no delay is configured.
+	DefaultRetryMaxDelay = 2 * time.Second
+)
+
+// RetryPolicy is how many times and how long after a transient failure a broadcast is retried.
+type RetryPolicy struct {
+	MaxRetries int           // a broadcast is not retried when it is 0
+	BaseDelay  time.Duration // backoff before the first retry, which doubles for every retry
+	MaxDelay   time.Duration // longest backoff before a retry
+}
+
+// Backoff returns the jittered backoff before the retry of the index, starting from 0.
+// The backoff doubles for every retry up to the max delay, and a random half of it is taken off
+// so that the broadcasts failed at once are not retried at once.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 0; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// RetryStats counts the retries of the broadcasts failed with an error class.
+type RetryStats struct {
+	Class     ErrorClass
+	Retries   uint64 // broadcasts retried after failing with the class
+	Recovered uint64 // broadcasts that succeeded after their last retry failed with the class
+	Failed    uint64 // broadcasts given up after failing with the class
+}
+
+// Retrier retries the broadcasts failed with a transient error class by the retry policy
+// and records the retries of every error class.
+type Retrier struct {
+	policy RetryPolicy
+
+	mu    sync.Mutex
+	stats map[ErrorClass]*RetryStats
+}
+
+// NewRetrier returns new Retrier object.
+func NewRetrier(policy RetryPolicy) *Retrier {
+	return &Retrier{
+		policy: policy,
+		stats:  make(map[ErrorClass]*RetryStats),
+	}
+}
+
+// Do calls broadcast until it does not fail, it fails with a permanent error class, the retries of the policy
+// run out or the context is done, and returns the result of its last call. A transaction rejected by its last
+// call is returned as a response rather than an error, so that its rejection is handled like any other.
+func (r *Retrier) Do(ctx context.Context, broadcast func() (*sdktypes.TxResponse, error)) (*sdktypes.TxResponse, error) {
+	var last ErrorClass
+	for retry := 0; ; retry++ {
+		resp, err := broadcast()
+
+		class, failed := ClassifyBroadcast(resp, err)
+		if !failed {
+			if retry > 0 {
+				r.record(last, func(s *RetryStats) { s.Recovered++ })
+			}
+			return resp, err
+		}
+		last = class
+
+		if !class.Transient() || retry >= r.policy.MaxRetries || ctx.Err() != nil {
+			r.record(class, func(s *RetryStats) { s.Failed++ })
+			return resp, err
+		}
+
+		r.record(class, func(s *RetryStats) { s.Retries++ })
+
+		timer := time.NewTimer(r.policy.Backoff(retry))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			r.record(class, func(s *RetryStats) { s.Failed++ })
+			return resp, err
+		}
+	}
+}
+
+func (r *Retrier) record(class ErrorClass, update func(s *RetryStats)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[class]
+	if !ok {
+		stats = &RetryStats{Class: class}
+		r.stats[class] = stats
+	}
+	update(stats)
+}
+
+// Stats returns the retries of every error class a broadcast failed with, sorted by the class.
+func (r *Retrier) Stats() []RetryStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]RetryStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Class < stats[j].Class
+	})
+
+	return stats
+}
@@ -0,0 +1,152 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyBroadcast(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		resp   *sdktypes.TxResponse
+		err    error
+		class  client.ErrorClass
+		failed bool
+	}{
+		{"accepted", &sdktypes.TxResponse{}, nil, "", false},
+		{"rejected", &sdktypes.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrInsufficientFunds.ABCICode()}, nil, "", false},
+		{"other codespace", &sdktypes.TxResponse{Codespace: "liquidity", Code: sdkerrors.ErrMempoolIsFull.ABCICode()}, nil, "", false},
+		{"mempool full", &sdktypes.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrMempoolIsFull.ABCICode()}, nil, client.ClassMempoolFull, true},
+		{"tx in cache", &sdktypes.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrTxInMempoolCache.ABCICode()}, nil, client.ClassTxInCache, true},
+		{"grpc unavailable", nil, fmt.Errorf("node0: %w", status.Error(codes.Unavailable, "connection refused")), client.ClassUnavailable, true},
+		{"grpc deadline", nil, status.Error(codes.DeadlineExceeded, "deadline"), client.ClassDeadlineExceeded, true},
+		{"grpc invalid", nil, status.Error(codes.InvalidArgument, "invalid"), client.ClassPermanent, true},
+		{"context deadline", nil, fmt.Errorf("post failed: %w", context.DeadlineExceeded), client.ClassDeadlineExceeded, true},
+		{"network", nil, &net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}, client.ClassUnavailable, true},
+		{"mempool full error", nil, fmt.Errorf("mempool is full: number of txs 5000"), client.ClassMempoolFull, true},
+		{"permanent", nil, fmt.Errorf("failed to broadcast transaction: 400 Bad Request"), client.ClassPermanent, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			class, failed := client.ClassifyBroadcast(tc.resp, tc.err)
+			require.Equal(t, tc.failed, failed)
+			require.Equal(t, tc.class, class)
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := client.RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for retry, delay := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		delay *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			backoff := policy.Backoff(retry)
+			require.True(t, backoff >= delay/2, "retry %d: %s", retry, backoff)
+			require.True(t, backoff <= delay, "retry %d: %s", retry, backoff)
+		}
+	}
+
+	require.Equal(t, time.Duration(0), client.RetryPolicy{}.Backoff(3))
+}
+
+func TestRetrierDo(t *testing.T) {
+	r := client.NewRetrier(client.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	mempoolFull := &sdktypes.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrMempoolIsFull.ABCICode()}
+
+	// recovers after a transient error and a full mempool
+	results := []struct {
+		resp *sdktypes.TxResponse
+		err  error
+	}{
+		{nil, status.Error(codes.Unavailable, "unavailable")},
+		{mempoolFull, nil},
+		{&sdktypes.TxResponse{}, nil},
+	}
+	var calls int
+	resp, err := r.Do(context.Background(), func() (*sdktypes.TxResponse, error) {
+		result := results[calls]
+		calls++
+		return result.resp, result.err
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), resp.Code)
+	require.Equal(t, 3, calls)
+
+	// gives up after the retries of the policy and returns the rejection as a response
+	calls = 0
+	resp, err = r.Do(context.Background(), func() (*sdktypes.TxResponse, error) {
+		calls++
+		return mempoolFull, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, mempoolFull, resp)
+	require.Equal(t, 4, calls)
+
+	// skips a permanent error
+	calls = 0
+	_, err = r.Do(context.Background(), func() (*sdktypes.TxResponse, error) {
+		calls++
+		return nil, status.Error(codes.InvalidArgument, "invalid")
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+
+	require.Equal(t, []client.RetryStats{
+		{Class: client.ClassMempoolFull, Retries: 4, Recovered: 1, Failed: 1},
+		{Class: client.ClassPermanent, Failed: 1},
+		{Class: client.ClassUnavailable, Retries: 1},
+	}, r.Stats())
+}
+
+func TestRetrierDoTimeoutInCache(t *testing.T) {
+	r := client.NewRetrier(client.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	inCache := &sdktypes.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrTxInMempoolCache.ABCICode()}
+
+	// the node received the transaction whose broadcast timed out, so the retries hit the mempool cache
+	// until they run out, and every one of them is counted in the tx-in-cache class
+	var calls int
+	resp, err := r.Do(context.Background(), func() (*sdktypes.TxResponse, error) {
+		calls++
+		if calls == 1 {
+			return nil, status.Error(codes.DeadlineExceeded, "deadline")
+		}
+		return inCache, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, inCache, resp)
+	require.Equal(t, 4, calls)
+
+	require.Equal(t, []client.RetryStats{
+		{Class: client.ClassDeadlineExceeded, Retries: 1},
+		{Class: client.ClassTxInCache, Retries: 2, Failed: 1},
+	}, r.Stats())
+}
+
+func TestRetrierDoCancel(t *testing.T) {
+	r := client.NewRetrier(client.RetryPolicy{MaxRetries: 10, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	_, err := r.Do(ctx, func() (*sdktypes.TxResponse, error) {
+		calls++
+		cancel()
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+	require.Equal(t, []client.RetryStats{{Class: client.ClassUnavailable, Failed: 1}}, r.Stats())
+}
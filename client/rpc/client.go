@@ -2,26 +2,44 @@ package rpc
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	rpc "github.com/tendermint/tendermint/rpc/client/http"
 	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
 )
 
+// newBlockHeadersCapacity is the number of NewBlockHeader events buffered for a subscriber that falls behind.
+const newBlockHeadersCapacity = 16
+
 // Client wraps RPC client connection.
 type Client struct {
 	rpcclient.Client
+	commit rpcclient.Client // client of broadcast_tx_commit, which waits for the transaction to be committed
 }
 
-// NewClient creates RPC client.
-func NewClient(rpcURL string, timeout int64) (*Client, error) {
+// NewClient creates RPC client whose requests time out after the timeout in seconds,
+// except broadcast_tx_commit, which times out after the commit timeout in seconds.
+func NewClient(rpcURL string, timeout int64, commitTimeout int64) (*Client, error) {
 	rpcClient, err := rpc.NewWithTimeout(rpcURL, "/websocket", uint(timeout))
 	if err != nil {
 		return &Client{}, fmt.Errorf("failed to connect RPC client: %s", err)
 	}
 
-	return &Client{rpcClient}, nil
+	commitClient, err := rpc.NewWithTimeout(rpcURL, "/websocket", uint(commitTimeout))
+	if err != nil {
+		return &Client{}, fmt.Errorf("failed to connect RPC client: %s", err)
+	}
+
+	return &Client{rpcClient, commitClient}, nil
 }
 
 // GetNetworkChainID returns network chain id.
@@ -38,3 +56,166 @@ func (c *Client) GetNetworkChainID(ctx context.Context) (string, error) {
 func (c *Client) GetStatus(ctx context.Context) (*tmctypes.ResultStatus, error) {
 	return c.Status(ctx)
 }
+
+// GetLatestBlockHeight returns the latest block height of the blockchain network.
+func (c *Client) GetLatestBlockHeight(ctx context.Context) (int64, error) {
+	status, err := c.Status(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get status: %v", err)
+	}
+
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// GetConsensusParams returns the consensus params of the latest block.
+func (c *Client) GetConsensusParams(ctx context.Context) (*tmproto.ConsensusParams, error) {
+	result, err := c.ConsensusParams(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consensus params: %v", err)
+	}
+
+	return &result.ConsensusParams, nil
+}
+
+// GetBlockSize returns the size in bytes and the number of transactions of the block at the height.
+func (c *Client) GetBlockSize(ctx context.Context, height int64) (int, int, error) {
+	result, err := c.Block(ctx, &height)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get block %d: %v", height, err)
+	}
+
+	return result.Block.Size(), len(result.Block.Txs), nil
+}
+
+// GetBlockTxResults returns the transactions of the block at the height and their DeliverTx results in order.
+func (c *Client) GetBlockTxResults(ctx context.Context, height int64) (tmtypes.Txs, []*abcitypes.ResponseDeliverTx, error) {
+	block, results, err := c.GetBlockWithResults(ctx, height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block.Txs, results, nil
+}
+
+// GetBlockWithResults returns the block at the height and the DeliverTx results of its transactions in order.
+func (c *Client) GetBlockWithResults(ctx context.Context, height int64) (*tmtypes.Block, []*abcitypes.ResponseDeliverTx, error) {
+	block, err := c.Block(ctx, &height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get block %d: %v", height, err)
+	}
+
+	results, err := c.BlockResults(ctx, &height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get block results %d: %v", height, err)
+	}
+
+	return block.Block, results.TxsResults, nil
+}
+
+// GetTx returns the committed transaction of the hash with its DeliverTx result.
+// It returns an error when the transaction is not committed.
+func (c *Client) GetTx(ctx context.Context, hash string) (*tmctypes.ResultTx, error) {
+	hashBytes, err := hex.DecodeString(hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx hash %s: %v", hash, err)
+	}
+
+	return c.Tx(ctx, hashBytes, false)
+}
+
+// SubscribeNewBlockHeaders subscribes to the NewBlockHeader events of the node as the subscriber over the websocket,
+// which is started when it is not running yet, and returns the channel of the headers of the blocks committed from
+// now on. Unlike the NewBlock events, the events carry no transactions, which keeps them small while blocks are full.
+// The subscription ends and the channel is closed when the context is done or the websocket is stopped.
+func (c *Client) SubscribeNewBlockHeaders(ctx context.Context, subscriber string) (<-chan tmtypes.Header, error) {
+	if !c.IsRunning() {
+		if err := c.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start websocket: %v", err)
+		}
+	}
+
+	query := tmtypes.QueryForEvent(tmtypes.EventNewBlockHeader).String()
+	events, err := c.Subscribe(ctx, subscriber, query, newBlockHeadersCapacity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to new block headers: %v", err)
+	}
+
+	headers := make(chan tmtypes.Header, newBlockHeadersCapacity)
+	go func() {
+		defer close(headers)
+		defer c.Unsubscribe(context.Background(), subscriber, query) // nolint: errcheck
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+
+				data, ok := event.Data.(tmtypes.EventDataNewBlockHeader)
+				if !ok {
+					continue
+				}
+
+				select {
+				case headers <- data.Header:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return headers, nil
+}
+
+// GetValidatorCount returns the number of validators of the latest block.
+func (c *Client) GetValidatorCount(ctx context.Context) (int, error) {
+	result, err := c.Validators(ctx, nil, nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get validators: %v", err)
+	}
+
+	return result.Total, nil
+}
+
+// Broadcast broadcasts transaction through broadcast_tx_async, broadcast_tx_sync or broadcast_tx_commit
+// by the broadcast mode and returns its response as the gRPC endpoint does.
+func (c *Client) Broadcast(ctx context.Context, txBytes []byte, mode sdktx.BroadcastMode) (*sdktypes.TxResponse, error) {
+	switch mode {
+	case sdktx.BroadcastMode_BROADCAST_MODE_ASYNC:
+		res, err := c.BroadcastTxAsync(ctx, txBytes)
+		if errRes := sdkclient.CheckTendermintError(err, txBytes); errRes != nil {
+			return errRes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return sdktypes.NewResponseFormatBroadcastTx(res), nil
+	case sdktx.BroadcastMode_BROADCAST_MODE_SYNC:
+		res, err := c.BroadcastTxSync(ctx, txBytes)
+		if errRes := sdkclient.CheckTendermintError(err, txBytes); errRes != nil {
+			return errRes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return sdktypes.NewResponseFormatBroadcastTx(res), nil
+	case sdktx.BroadcastMode_BROADCAST_MODE_BLOCK:
+		res, err := c.commit.BroadcastTxCommit(ctx, txBytes)
+		if errRes := sdkclient.CheckTendermintError(err, txBytes); errRes != nil {
+			return errRes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return sdktypes.NewResponseFormatBroadcastTxCommit(res), nil
+	default:
+		return nil, fmt.Errorf("unsupported broadcast mode %s", mode)
+	}
+}
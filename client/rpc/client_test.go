@@ -3,11 +3,15 @@ package rpc_test
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/b-harvest/cosmos-module-stress-test/client/rpc"
 	"github.com/b-harvest/cosmos-module-stress-test/codec"
 
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+
 	"github.com/test-go/testify/require"
 )
 
@@ -20,7 +24,7 @@ var (
 func TestMain(m *testing.M) {
 	codec.SetCodec()
 
-	c, _ = rpc.NewClient(rpcAddress, 5)
+	c, _ = rpc.NewClient(rpcAddress, 5, 60)
 
 	os.Exit(m.Run())
 }
@@ -31,3 +35,95 @@ func TestGetNetworkChainID(t *testing.T) {
 
 	t.Log(chainID)
 }
+
+func TestGetLatestBlockHeight(t *testing.T) {
+	height, err := c.GetLatestBlockHeight(context.Background())
+	require.NoError(t, err)
+
+	t.Log(height)
+}
+
+func TestGetConsensusParams(t *testing.T) {
+	params, err := c.GetConsensusParams(context.Background())
+	require.NoError(t, err)
+	require.True(t, params.Block.MaxBytes > 0)
+
+	t.Log(params.Block.MaxBytes, params.Block.MaxGas)
+}
+
+func TestGetBlockSize(t *testing.T) {
+	height, err := c.GetLatestBlockHeight(context.Background())
+	require.NoError(t, err)
+
+	size, numTxs, err := c.GetBlockSize(context.Background(), height)
+	require.NoError(t, err)
+	require.True(t, size > 0)
+
+	t.Log(size, numTxs)
+}
+
+func TestGetValidatorCount(t *testing.T) {
+	count, err := c.GetValidatorCount(context.Background())
+	require.NoError(t, err)
+	require.True(t, count > 0)
+
+	t.Log(count)
+}
+
+func TestGetBlockTxResults(t *testing.T) {
+	height, err := c.GetLatestBlockHeight(context.Background())
+	require.NoError(t, err)
+
+	txs, results, err := c.GetBlockTxResults(context.Background(), height)
+	require.NoError(t, err)
+	require.Len(t, results, len(txs))
+
+	t.Log(len(txs))
+}
+
+func TestGetBlockWithResults(t *testing.T) {
+	height, err := c.GetLatestBlockHeight(context.Background())
+	require.NoError(t, err)
+
+	block, results, err := c.GetBlockWithResults(context.Background(), height)
+	require.NoError(t, err)
+	require.Equal(t, height, block.Height)
+	require.Len(t, results, len(block.Txs))
+
+	t.Log(block.Time)
+}
+
+func TestGetTx(t *testing.T) {
+	_, err := c.GetTx(context.Background(), "not a hash")
+	require.Error(t, err)
+
+	// a transaction that was never broadcast is not found
+	_, err = c.GetTx(context.Background(), strings.Repeat("AB", 32))
+	require.Error(t, err)
+}
+
+func TestSubscribeNewBlockHeaders(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	headers, err := c.SubscribeNewBlockHeaders(ctx, "test")
+	require.NoError(t, err)
+
+	first := <-headers
+	second := <-headers
+	require.Equal(t, first.Height+1, second.Height)
+
+	// the channel is closed once the subscription ends
+	cancel()
+	for range headers {
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	// bytes that are not a transaction are rejected by CheckTx
+	resp, err := c.Broadcast(context.Background(), []byte("not a transaction"), sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+	require.NoError(t, err)
+	require.NotEqual(t, uint32(0), resp.Code)
+
+	t.Log(resp.Code, resp.RawLog)
+}
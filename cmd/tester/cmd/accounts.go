@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/b-harvest/cosmos-module-stress-test/config"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// worker is an account that signs transactions of its messages in a round.
+type worker struct {
+	wallet.Account
+	msgs []sdktypes.Msg
+}
+
+// recoverAccounts returns the master account and the worker accounts configured in the config.
+// The keys are loaded from the keyring when a keyring backend is configured and derived from the mnemonic otherwise.
+// The worker accounts are read from the accounts file instead when it is configured.
+// When a multisig threshold is configured, every multisig_keys consecutive worker keys make up a multisig worker account.
+func recoverAccounts(cfg *config.Config) (wallet.Account, []wallet.Account, error) {
+	if cfg.Custom.MultisigThreshold == 0 {
+		return recoverWorkerKeys(cfg, 1)
+	}
+
+	numKeys := cfg.Custom.MultisigKeys
+	if numKeys < cfg.Custom.MultisigThreshold {
+		return wallet.Account{}, nil, fmt.Errorf("multisig_keys %d must not be less than multisig_threshold %d", numKeys, cfg.Custom.MultisigThreshold)
+	}
+
+	master, keys, err := recoverWorkerKeys(cfg, numKeys)
+	if err != nil {
+		return wallet.Account{}, nil, err
+	}
+
+	accounts, err := wallet.NewMultisigAccounts(keys, int(cfg.Custom.MultisigThreshold), int(numKeys))
+	if err != nil {
+		return wallet.Account{}, nil, err
+	}
+
+	return master, accounts, nil
+}
+
+// recoverWorkerKeys returns the master account and numKeys keys for each worker account.
+func recoverWorkerKeys(cfg *config.Config, numKeys uint32) (wallet.Account, []wallet.Account, error) {
+	if cfg.Custom.AccountsFile == "" {
+		numAccounts := cfg.Custom.NumAccounts
+		if numAccounts == 0 {
+			numAccounts = 1
+		}
+
+		return recoverKeys(cfg, numAccounts*numKeys)
+	}
+
+	master, _, err := recoverKeys(cfg, 1)
+	if err != nil {
+		return wallet.Account{}, nil, err
+	}
+
+	accounts, err := wallet.ReadAccountsFile(cfg.Custom.AccountsFile)
+	if err != nil {
+		return wallet.Account{}, nil, err
+	}
+
+	// every account from the address index is used unless the number of accounts is set
+	start := int(cfg.Custom.AddressIndex)
+	end := len(accounts)
+	if cfg.Custom.NumAccounts != 0 {
+		end = start + int(cfg.Custom.NumAccounts*numKeys)
+	}
+
+	if start >= end || end > len(accounts) {
+		return wallet.Account{}, nil, fmt.Errorf("accounts file %s holds %d accounts; cannot use accounts %d to %d", cfg.Custom.AccountsFile, len(accounts), start, end-1)
+	}
+
+	return master, accounts[start:end], nil
+}
+
+// recoverKeys returns the master account and numAccounts worker accounts from the keyring or the mnemonic.
+func recoverKeys(cfg *config.Config, numAccounts uint32) (wallet.Account, []wallet.Account, error) {
+	if cfg.Keyring != nil && cfg.Keyring.Backend != "" {
+		return recoverAccountsFromKeyring(cfg.Keyring, cfg.Custom.AddressIndex, numAccounts)
+	}
+
+	accAddr, privKey, err := wallet.RecoverAccountFromMnemonic(cfg.Custom.Mnemonic, "")
+	if err != nil {
+		return wallet.Account{}, nil, fmt.Errorf("failed to retrieve account from mnemonic: %s", err)
+	}
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(cfg.Custom.Mnemonic, "", cfg.Custom.AccountIndex, cfg.Custom.AddressIndex, numAccounts)
+	if err != nil {
+		return wallet.Account{}, nil, fmt.Errorf("failed to retrieve accounts from mnemonic: %s", err)
+	}
+
+	return wallet.Account{Address: accAddr, Signer: privKey}, accounts, nil
+}
+
+// recoverAccountsFromKeyring loads the master key and the worker keys from the keyring.
+// Without a worker key prefix, the master account is the only worker account.
+func recoverAccountsFromKeyring(cfg *config.KeyringConfig, index uint32, numAccounts uint32) (wallet.Account, []wallet.Account, error) {
+	kr, err := wallet.NewKeyring(cfg.Backend, cfg.Dir)
+	if err != nil {
+		return wallet.Account{}, nil, err
+	}
+
+	master, err := wallet.RecoverAccountFromKeyring(kr, cfg.KeyName)
+	if err != nil {
+		return wallet.Account{}, nil, err
+	}
+
+	if cfg.WorkerKeyPrefix == "" {
+		if numAccounts > 1 {
+			return wallet.Account{}, nil, fmt.Errorf("worker_key_prefix must be set to use %d accounts from the keyring", numAccounts)
+		}
+
+		return master, []wallet.Account{master}, nil
+	}
+
+	accounts := make([]wallet.Account, 0, numAccounts)
+	for i := uint32(0); i < numAccounts; i++ {
+		acc, err := wallet.RecoverAccountFromKeyring(kr, fmt.Sprintf("%s%d", cfg.WorkerKeyPrefix, index+i))
+		if err != nil {
+			return wallet.Account{}, nil, err
+		}
+		accounts = append(accounts, acc)
+	}
+
+	return master, accounts, nil
+}
+
+// newWorkers returns the workers that sign txNum transactions in a round.
+// Transactions are spread over the accounts, so no more than txNum accounts are used.
+func newWorkers(accounts []wallet.Account, txNum int) []*worker {
+	if txNum < len(accounts) {
+		accounts = accounts[:txNum]
+	}
+
+	workers := make([]*worker, 0, len(accounts))
+	for _, acc := range accounts {
+		workers = append(workers, &worker{Account: acc})
+	}
+
+	return workers
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagRate        = "rate"
+	flagConcurrency = "concurrency"
+)
+
+// BlastCmd broadcasts the transactions of a presigned tx file written by the presign command.
+// This command is useful to benchmark broadcasting alone, as the transactions are signed ahead of time.
+func BlastCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "blast [presigned-tx-file]",
+		Short:   "broadcast the transactions of a presigned tx file as fast as possible or at a rate.",
+		Aliases: []string{"b"},
+		Args:    cobra.ExactArgs(1),
+		Long: `Broadcast the transactions of a presigned tx file written by the presign command in order.
+The transactions are broadcast as fast as possible unless a rate of transactions per second is given.
+With a concurrency above 1, the accounts are spread over concurrent broadcasters and the transactions of
+an account are broadcast in order by the same broadcaster, so that their sequences reach the node in order.
+
+Example: $ tester blast presigned.jsonl --rate 200 --concurrency 4
+
+[presigned-tx-file]: presigned tx file written by the presign command
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			rate, err := cmd.Flags().GetFloat64(flagRate)
+			if err != nil {
+				return err
+			}
+
+			if rate < 0 {
+				return fmt.Errorf("rate must not be negative: %f", rate)
+			}
+
+			concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+			if err != nil {
+				return err
+			}
+
+			if concurrency <= 0 {
+				return fmt.Errorf("concurrency must be positive: %d", concurrency)
+			}
+
+			txs, err := tx.ReadPresignedTxFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			if len(txs) == 0 {
+				return fmt.Errorf("presigned tx file %s holds no transactions", args[0])
+			}
+
+			client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+			if err != nil {
+				return err
+			}
+			defer client.Stop() // nolint: errcheck
+
+			broadcaster, err := newTxBroadcaster(client, cfg)
+			if err != nil {
+				return err
+			}
+			defer broadcaster.close() // nolint: errcheck
+
+			log.Info().Msgf("broadcasting %d transactions; rate:%.2f; concurrency:%d; broadcastMode:%s; transport:%s; policy:%s",
+				len(txs), rate, concurrency, broadcaster.modeName, broadcaster.transport, broadcaster.policy)
+
+			stats := blast(ctx, broadcaster, txs, rate, concurrency)
+
+			log.Info().Msgf("broadcast:%d; failed:%d; rejected:%d; elapsed:%s; tps:%.2f",
+				stats.broadcast, stats.failed, stats.rejected, stats.elapsed, float64(stats.broadcast)/stats.elapsed.Seconds())
+
+			broadcaster.logRetries()
+			broadcaster.logEndpoints(ctx)
+
+			return nil
+		},
+	}
+	cmd.Flags().Float64(flagRate, 0, "Transactions broadcast per second. Transactions are broadcast as fast as possible when it is 0.")
+	cmd.Flags().Int(flagConcurrency, 1, "Number of concurrent broadcasters.")
+	return cmd
+}
+
+// blastStats counts the results of broadcasting a presigned tx file.
+type blastStats struct {
+	broadcast uint64 // accepted by the broadcast endpoint
+	failed    uint64 // failed to be broadcast
+	rejected  uint64 // broadcast with a non-zero code
+	elapsed   time.Duration
+}
+
+// blast broadcasts the transactions in order with the broadcaster, pacing them at the rate unless it is 0.
+// Every account is owned by one of the concurrent broadcasters, which broadcasts its transactions in order.
+func blast(ctx context.Context, b *txBroadcaster, txs []tx.PresignedTx, rate float64, concurrency int) blastStats {
+	var stats blastStats
+	var wg sync.WaitGroup
+
+	// the accounts are assigned in the order they are first seen, and every queue holds all the transactions of
+	// its accounts, so that the pacing loop never blocks on a broadcaster whose accounts have more transactions
+	owners := make(map[string]int)
+	sizes := make([]int, concurrency)
+	for _, ptx := range txs {
+		owner, ok := owners[ptx.Address]
+		if !ok {
+			owner = len(owners) % concurrency
+			owners[ptx.Address] = owner
+		}
+		sizes[owner]++
+	}
+
+	queues := make([]chan tx.PresignedTx, concurrency)
+	for i := range queues {
+		queues[i] = make(chan tx.PresignedTx, sizes[i])
+
+		wg.Add(1)
+		go func(queue <-chan tx.PresignedTx) {
+			defer wg.Done()
+
+			for ptx := range queue {
+				resp, err := b.broadcastWithRetries(ctx, ptx.Address, ptx.TxBytes)
+				if err != nil {
+					atomic.AddUint64(&stats.failed, 1)
+					log.Debug().Msgf("failed to broadcast tx of %s with sequence %d: %s", ptx.Address, ptx.Sequence, err)
+					continue
+				}
+				atomic.AddUint64(&stats.broadcast, 1)
+
+				if resp.Code != 0 {
+					atomic.AddUint64(&stats.rejected, 1)
+					log.Debug().Msgf("rejected tx of %s with sequence %d: code:%d; log:%s", ptx.Address, ptx.Sequence, resp.Code, resp.RawLog)
+				}
+			}
+		}(queues[i])
+	}
+
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Duration(float64(time.Second) / rate)
+	}
+
+	start := time.Now()
+	for i, ptx := range txs {
+		if interval > 0 {
+			time.Sleep(time.Until(start.Add(time.Duration(i) * interval)))
+		}
+
+		queues[owners[ptx.Address]] <- ptx
+	}
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	stats.elapsed = time.Since(start)
+
+	return stats
+}
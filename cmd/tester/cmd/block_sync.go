@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const flagBlockSync = "block-sync"
+
+// blockSync ties the rounds of a run to the blocks of the node: every round is broadcast right after a new block is
+// committed, and the next round waits for the following block, so that the transactions of a round reach the mempool
+// at the start of a block interval. A nil blockSync does not wait, which runs the rounds back to back.
+type blockSync struct {
+	headers <-chan tmtypes.Header
+	last    int64 // height of the block the last round was broadcast after
+	rounds  int
+	skipped int64 // blocks committed while the last round was signed and broadcast, which no round followed
+}
+
+// newBlockSync subscribes to the new block headers of the node when the block-sync flag is set and returns nil otherwise.
+func newBlockSync(ctx context.Context, cmd *cobra.Command, c *client.Client) (*blockSync, error) {
+	enabled, err := cmd.Flags().GetBool(flagBlockSync)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	headers, err := c.RPC.SubscribeNewBlockHeaders(ctx, "tester")
+	if err != nil {
+		return nil, err
+	}
+
+	return &blockSync{headers: headers}, nil
+}
+
+// wait waits for a block after the block the last round was broadcast after to be committed. When such blocks
+// were committed while the round was signed and broadcast, the latest of them is taken right away, and the blocks
+// before it are counted as skipped, since no round followed them.
+func (s *blockSync) wait(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	// the latest of the headers received while the last round was signed and broadcast
+	var header tmtypes.Header
+	for buffered := true; buffered; {
+		select {
+		case h, ok := <-s.headers:
+			if !ok {
+				return fmt.Errorf("new block subscription ended")
+			}
+			header = h
+		default:
+			buffered = false
+		}
+	}
+
+	for header.Height <= s.last {
+		select {
+		case h, ok := <-s.headers:
+			if !ok {
+				return fmt.Errorf("new block subscription ended")
+			}
+			header = h
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if s.last > 0 && header.Height > s.last+1 {
+		skipped := header.Height - s.last - 1
+		s.skipped += skipped
+		log.Warn().Msgf("round took longer than a block; height:%d; skippedBlocks:%d", header.Height, skipped)
+	}
+	s.last = header.Height
+	s.rounds++
+
+	log.Debug().Msgf("new block; height:%d", header.Height)
+
+	return nil
+}
+
+// log logs how many rounds followed a block and how many blocks no round followed.
+func (s *blockSync) log() {
+	if s == nil {
+		return
+	}
+
+	log.Info().Msgf("blockSync; rounds:%d; lastHeight:%d; skippedBlocks:%d", s.rounds, s.last, s.skipped)
+}
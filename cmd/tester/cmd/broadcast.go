@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/config"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// txBroadcaster broadcasts transactions in the broadcast mode and over the transport of a run and records their results.
+// The sync mode records the CheckTx code and log of every transaction, and the block mode records
+// the gas used and the events of every transaction as well.
+// The transactions are distributed over the configured endpoints by the broadcast policy, and are broadcast to
+// the configured node when no endpoints are configured.
+type txBroadcaster struct {
+	client     *client.Client
+	multi      *client.MultiBroadcaster
+	retrier    *client.Retrier
+	transport  string
+	policy     client.BroadcastPolicy
+	dialed     bool // whether the endpoints were dialed for the run rather than shared with the client
+	mode       sdktx.BroadcastMode
+	modeName   string
+	lcdAddress string
+	txLogLevel zerolog.Level // level the response of every transaction is logged at
+
+	mu        sync.Mutex
+	txs       uint64
+	skipped   uint64 // transactions whose broadcast failed for good
+	codes     map[string]uint64
+	gasWanted int64
+	gasUsed   int64
+	events    map[string]uint64
+}
+
+// newTxBroadcaster returns the broadcaster of the broadcast mode, the transport and the policy of the run.
+// The transport and the policy of the broadcast-transport and broadcast-policy flags override the ones of the config.
+func newTxBroadcaster(c *client.Client, cfg *config.Config) (*txBroadcaster, error) {
+	mode, modeName, err := parseBroadcastMode(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := broadcastTransport
+	if transport == "" {
+		transport = cfg.Custom.BroadcastTransport
+	}
+	if transport == "" {
+		transport = client.TransportGRPC
+	}
+
+	policyName := broadcastPolicy
+	if policyName == "" {
+		policyName = cfg.Custom.BroadcastPolicy
+	}
+
+	policy, err := client.ParseBroadcastPolicy(policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []client.Endpoint
+	dialed := len(cfg.Endpoints) > 0
+	if dialed {
+		for _, e := range cfg.Endpoints {
+			endpoint, err := client.DialEndpoint(transport, e.RPC, e.GRPC, e.LCD)
+			if err != nil {
+				return nil, err
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+	} else {
+		broadcaster, err := c.NewBroadcaster(transport, cfg.LCD.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		name := cfg.GRPC.Address
+		switch transport {
+		case client.TransportRPC:
+			name = cfg.RPC.Address
+		case client.TransportLCD:
+			name = cfg.LCD.Address
+		}
+
+		endpoints = append(endpoints, client.Endpoint{Name: name, Broadcaster: broadcaster, RPC: c.RPC})
+	}
+
+	multi, err := client.NewMultiBroadcaster(policy, endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy, err := parseRetryPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txBroadcaster{
+		client:     c,
+		multi:      multi,
+		retrier:    client.NewRetrier(retryPolicy),
+		transport:  transport,
+		policy:     policy,
+		dialed:     dialed,
+		mode:       mode,
+		modeName:   modeName,
+		lcdAddress: cfg.LCD.Address,
+		txLogLevel: zerolog.InfoLevel,
+		codes:      make(map[string]uint64),
+		events:     make(map[string]uint64),
+	}, nil
+}
+
+// close closes the connections to the endpoints dialed for the run.
+func (b *txBroadcaster) close() error {
+	if !b.dialed {
+		return nil
+	}
+
+	return b.multi.Close()
+}
+
+// parseBroadcastMode returns the broadcast mode of the broadcast-mode flag, or of the config when the flag is not set,
+// with its name.
+func parseBroadcastMode(cfg *config.Config) (sdktx.BroadcastMode, string, error) {
+	modeName := broadcastMode
+	if modeName == "" {
+		modeName = cfg.Custom.BroadcastMode
+	}
+
+	mode, err := tx.ParseBroadcastMode(modeName)
+	if err != nil {
+		return sdktx.BroadcastMode_BROADCAST_MODE_UNSPECIFIED, "", err
+	}
+
+	if modeName == "" {
+		modeName = tx.BroadcastModeAsync
+	}
+
+	return mode, modeName, nil
+}
+
+// parseRetryPolicy returns the retry policy of the config, whose unset limits are the defaults.
+func parseRetryPolicy(cfg *config.Config) (client.RetryPolicy, error) {
+	policy := client.RetryPolicy{
+		MaxRetries: cfg.Custom.MaxRetries,
+		BaseDelay:  client.DefaultRetryBaseDelay,
+		MaxDelay:   client.DefaultRetryMaxDelay,
+	}
+
+	switch {
+	case policy.MaxRetries == 0:
+		policy.MaxRetries = client.DefaultMaxRetries
+	case policy.MaxRetries < 0:
+		policy.MaxRetries = 0
+	}
+
+	if cfg.Custom.RetryBaseDelay != "" {
+		delay, err := time.ParseDuration(cfg.Custom.RetryBaseDelay)
+		if err != nil {
+			return client.RetryPolicy{}, fmt.Errorf("failed to parse retry base delay: %s", err)
+		}
+		policy.BaseDelay = delay
+	}
+
+	if cfg.Custom.RetryMaxDelay != "" {
+		delay, err := time.ParseDuration(cfg.Custom.RetryMaxDelay)
+		if err != nil {
+			return client.RetryPolicy{}, fmt.Errorf("failed to parse retry max delay: %s", err)
+		}
+		policy.MaxDelay = delay
+	}
+
+	if policy.BaseDelay < 0 || policy.MaxDelay < policy.BaseDelay {
+		return client.RetryPolicy{}, fmt.Errorf("retry delays must not be negative and the max delay must not be less than the base delay")
+	}
+
+	return policy, nil
+}
+
+// broadcastWithRetries broadcasts the transaction of the account over the endpoints and retries the broadcasts
+// that fail with a transient error class by the retry policy, without logging or recording the response.
+func (b *txBroadcaster) broadcastWithRetries(ctx context.Context, address string, txBytes []byte) (*sdktypes.TxResponse, error) {
+	return b.retrier.Do(ctx, func() (*sdktypes.TxResponse, error) {
+		return b.multi.Broadcast(ctx, address, txBytes, b.mode)
+	})
+}
+
+// broadcast broadcasts the transaction of the account, logs its response by the broadcast mode and records it.
+// The broadcasts that fail with a transient error class are retried by the retry policy, and a transaction whose
+// broadcast failed for good is recorded as skipped.
+func (b *txBroadcaster) broadcast(ctx context.Context, address string, txBytes []byte) (*sdktypes.TxResponse, error) {
+	txResp, err := b.broadcastWithRetries(ctx, address, txBytes)
+	if err != nil {
+		b.mu.Lock()
+		b.skipped++
+		b.mu.Unlock()
+
+		return nil, fmt.Errorf("failed to broadcast transaction: %s", err)
+	}
+
+	code := "ok"
+	if txResp.Code != 0 {
+		code = fmt.Sprintf("%s/%d", txResp.Codespace, txResp.Code)
+	}
+
+	switch b.mode {
+	case sdktx.BroadcastMode_BROADCAST_MODE_SYNC:
+		log.WithLevel(b.txLogLevel).Msgf("txHash:%s; code:%s; log:%s", txResp.TxHash, code, txResp.RawLog)
+	case sdktx.BroadcastMode_BROADCAST_MODE_BLOCK:
+		log.WithLevel(b.txLogLevel).Msgf("txHash:%s; height:%d; code:%s; gasWanted:%d; gasUsed:%d; events:%s",
+			txResp.TxHash, txResp.Height, code, txResp.GasWanted, txResp.GasUsed, formatEvents(txResp.Logs))
+		if txResp.Code != 0 {
+			log.WithLevel(b.txLogLevel).Msgf("txHash:%s; log:%s", txResp.TxHash, txResp.RawLog)
+		}
+	default:
+		log.WithLevel(b.txLogLevel).Msgf("%s/cosmos/tx/v1beta1/txs/%s", b.lcdAddress, txResp.TxHash)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.txs++
+	if b.mode == sdktx.BroadcastMode_BROADCAST_MODE_ASYNC {
+		return txResp, nil
+	}
+
+	b.codes[code]++
+	b.gasWanted += txResp.GasWanted
+	b.gasUsed += txResp.GasUsed
+	for _, msgLog := range txResp.Logs {
+		for _, event := range msgLog.Events {
+			b.events[event.Type]++
+		}
+	}
+
+	return txResp, nil
+}
+
+// log logs the results of the transactions broadcast in a run and the results of every endpoint.
+func (b *txBroadcaster) log(ctx context.Context) {
+	b.mu.Lock()
+	if b.mode == sdktx.BroadcastMode_BROADCAST_MODE_ASYNC {
+		log.Info().Msgf("broadcastMode:%s; transport:%s; policy:%s; txs:%d; skipped:%d", b.modeName, b.transport, b.policy, b.txs, b.skipped)
+	} else {
+		log.Info().Msgf("broadcastMode:%s; transport:%s; policy:%s; txs:%d; skipped:%d; codes:%s", b.modeName, b.transport, b.policy, b.txs, b.skipped, formatCounts(b.codes))
+	}
+
+	if b.mode == sdktx.BroadcastMode_BROADCAST_MODE_BLOCK && b.txs > 0 {
+		log.Info().Msgf("gasWanted:%d; gasUsed:%d; avgGasUsed:%d; events:%s",
+			b.gasWanted, b.gasUsed, b.gasUsed/int64(b.txs), formatCounts(b.events))
+	}
+	b.mu.Unlock()
+
+	b.logRetries()
+	b.logEndpoints(ctx)
+}
+
+// logRetries logs how many broadcasts were retried, recovered and given up after failing with every error class.
+func (b *txBroadcaster) logRetries() {
+	for _, stats := range b.retrier.Stats() {
+		log.Info().Msgf("errorClass:%s; retries:%d; recovered:%d; failed:%d", stats.Class, stats.Retries, stats.Recovered, stats.Failed)
+	}
+}
+
+// logEndpoints logs the broadcast results and the latencies of every endpoint with the size of its mempool,
+// which shows how the mempools of the nodes diverge.
+func (b *txBroadcaster) logEndpoints(ctx context.Context) {
+	endpoints := b.multi.Endpoints()
+	for i, stats := range b.multi.Stats() {
+		mempool := "unknown"
+		if endpoints[i].RPC != nil {
+			result, err := endpoints[i].RPC.NumUnconfirmedTxs(ctx)
+			if err != nil {
+				log.Debug().Msgf("failed to get mempool size of %s: %s", stats.Name, err)
+			} else {
+				mempool = fmt.Sprintf("%d txs/%d bytes", result.Total, result.TotalBytes)
+			}
+		}
+
+		log.Info().Msgf("endpoint:%s; sent:%d; failed:%d; rejected:%d; avgLatency:%s; maxLatency:%s; mempool:%s",
+			stats.Name, stats.Sent, stats.Failed, stats.Rejected, stats.AvgLatency(), stats.MaxLatency, mempool)
+	}
+}
+
+// formatEvents formats the events of the message logs as type{key=value,...}.
+func formatEvents(logs sdktypes.ABCIMessageLogs) string {
+	var events []string
+	for _, msgLog := range logs {
+		for _, event := range msgLog.Events {
+			attrs := make([]string, 0, len(event.Attributes))
+			for _, attr := range event.Attributes {
+				attrs = append(attrs, attr.Key+"="+attr.Value)
+			}
+			events = append(events, fmt.Sprintf("%s{%s}", event.Type, strings.Join(attrs, ",")))
+		}
+	}
+
+	return strings.Join(events, " ")
+}
+
+// formatCounts formats the counts as sorted key=count pairs.
+func formatCounts(counts map[string]uint64) string {
+	pairs := make([]string, 0, len(counts))
+	for key, count := range counts {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", key, count))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, " ")
+}
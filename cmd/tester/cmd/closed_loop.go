@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/load"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	tmctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const (
+	flagClosedLoop    = "closed-loop"
+	flagCommitTimeout = "commit-timeout"
+	flagPollInterval  = "poll-interval"
+
+	// outcomes of the transactions of a closed-loop run
+	outcomeCommitted       = "committed"
+	outcomeDeliverFailed   = "deliver-failed"
+	outcomeCheckTxRejected = "checktx-rejected"
+	outcomeBroadcastFailed = "broadcast-failed"
+	outcomeTimeout         = "timeout"
+	outcomeOther           = "other"
+)
+
+// runClosedLoad runs a closed-loop load of the transactions of the messages built by build. Every worker owns one
+// account and sends a transaction, waits until it is committed or rejected by CheckTx and then sends the next one,
+// so the number of workers is the load and the latency is the end-to-end latency of a transaction.
+func runClosedLoad(cmd *cobra.Command, build loadMsgs) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	err := SetLogger(logLevel)
+	if err != nil {
+		return err
+	}
+
+	loadCfg, refresh, err := parseLoadFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	commitTimeout, err := cmd.Flags().GetDuration(flagCommitTimeout)
+	if err != nil {
+		return err
+	}
+
+	pollInterval, err := cmd.Flags().GetDuration(flagPollInterval)
+	if err != nil {
+		return err
+	}
+
+	if len(loadCfg.Profile) > 0 {
+		return fmt.Errorf("profile is not supported in the closed-loop mode, whose load is the number of workers")
+	}
+
+	if commitTimeout <= 0 || pollInterval <= 0 {
+		return fmt.Errorf("commit-timeout and poll-interval must be positive")
+	}
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	// every worker owns one of the derived accounts
+	if loadCfg.Workers > 0 {
+		cfg.Custom.NumAccounts = uint32(loadCfg.Workers)
+	}
+
+	client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+	if err != nil {
+		return err
+	}
+	defer client.Stop() // nolint: errcheck
+
+	chainID, err := client.RPC.GetNetworkChainID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, accounts, err := recoverAccounts(cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(accounts) == 0 {
+		return fmt.Errorf("no accounts to broadcast transactions with")
+	}
+
+	workers := newWorkers(accounts, len(accounts))
+	refreshed := make([]time.Time, len(workers))
+
+	seqs := tx.NewSequenceManager(client)
+
+	broadcaster, err := newTxBroadcaster(client, cfg)
+	if err != nil {
+		return err
+	}
+	defer broadcaster.close() // nolint: errcheck
+	broadcaster.txLogLevel = zerolog.DebugLevel
+
+	// a worker waits for the CheckTx result of its transaction, which is not returned in the async mode
+	if broadcaster.mode == sdktx.BroadcastMode_BROADCAST_MODE_ASYNC {
+		broadcaster.mode = sdktx.BroadcastMode_BROADCAST_MODE_SYNC
+		broadcaster.modeName = tx.BroadcastModeSync
+	}
+
+	t, err := newTransaction(client, chainID, cfg)
+	if err != nil {
+		return err
+	}
+
+	closedCfg := load.ClosedConfig{
+		Workers:  len(workers),
+		Duration: loadCfg.Duration,
+		Count:    loadCfg.Count,
+	}
+
+	log.Info().Msgf("closedLoop; workers:%d; duration:%s; count:%d; broadcastMode:%s; transport:%s; policy:%s",
+		closedCfg.Workers, closedCfg.Duration, closedCfg.Count, broadcaster.modeName, broadcaster.transport, broadcaster.policy)
+
+	outcomes := newOutcomeStats()
+
+	op := func(ctx context.Context, job load.Job) error {
+		w := workers[job.Worker]
+
+		if time.Since(refreshed[job.Worker]) >= refresh {
+			err := seqs.Refresh(ctx, w.Address)
+			if err != nil {
+				log.Debug().Msgf("failed to refresh sequence of %s: %s", w.Address, err)
+				outcomes.add(outcomeOther)
+				return err
+			}
+
+			w.msgs, err = build(ctx, client, t, w.Address)
+			if err != nil {
+				log.Debug().Msgf("failed to create msg of %s: %s", w.Address, err)
+				outcomes.add(outcomeOther)
+				return err
+			}
+			refreshed[job.Worker] = time.Now()
+		}
+
+		resp, err := signAndBroadcastNext(ctx, broadcaster, t, seqs, w)
+		switch {
+		case err != nil && resp == nil:
+			log.Debug().Msgf("tx of %s failed: %s", w.Address, err)
+			outcomes.add(outcomeBroadcastFailed)
+			return err
+		case err != nil && resp.Height > 0:
+			// the block mode returns the DeliverTx result of a committed transaction
+			outcomes.add(outcomeDeliverFailed)
+			return err
+		case err != nil:
+			outcomes.add(outcomeCheckTxRejected)
+			return err
+		case resp.Height > 0:
+			outcomes.add(outcomeCommitted)
+			return nil
+		}
+
+		result, err := waitForCommit(ctx, client, resp.TxHash, commitTimeout, pollInterval)
+		if err != nil {
+			log.Debug().Msgf("tx of %s not committed: %s", w.Address, err)
+			outcomes.add(outcomeTimeout)
+			return err
+		}
+
+		if result.TxResult.Code != 0 {
+			outcomes.add(outcomeDeliverFailed)
+			return fmt.Errorf("tx %s failed: code:%s/%d; log:%s", resp.TxHash, result.TxResult.Codespace, result.TxResult.Code, result.TxResult.Log)
+		}
+		outcomes.add(outcomeCommitted)
+
+		return nil
+	}
+
+	result, err := load.RunClosed(ctx, closedCfg, op, logClosedLoadReport)
+	if err != nil {
+		return err
+	}
+
+	total := result.Total
+	log.Info().Msgf("total; elapsed:%s; workers:%d; throughput:%.2f; completed:%d; failed:%d; avgLatency:%s; maxLatency:%s; outcomes:%s",
+		total.Elapsed.Round(time.Millisecond), closedCfg.Workers, total.Rate(), total.Completed, total.Failed,
+		total.AvgLatency.Round(time.Millisecond), total.MaxLatency.Round(time.Millisecond), outcomes.format())
+
+	logSequenceStats(seqs, workers, broadcaster.mode)
+	broadcaster.log(context.Background())
+	logSignModes(t)
+	logGasEstimates(t)
+
+	return nil
+}
+
+// waitForCommit polls the node at the interval until the transaction of the hash is committed or the timeout passed.
+func waitForCommit(ctx context.Context, c *client.Client, hash string, timeout time.Duration, interval time.Duration) (*tmctypes.ResultTx, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		result, err := c.RPC.GetTx(ctx, hash)
+		if err == nil {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("tx %s not committed within %s: %s", hash, timeout, err)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// logClosedLoadReport logs the throughput and the end-to-end latencies of a second of a closed-loop run.
+func logClosedLoadReport(r load.Report) {
+	log.Info().Msgf("elapsed:%s; throughput:%.2f; completed:%d; failed:%d; inFlight:%d; avgLatency:%s; maxLatency:%s",
+		r.Elapsed.Round(time.Second), r.Rate(), r.Completed, r.Failed, r.Pending,
+		r.AvgLatency.Round(time.Millisecond), r.MaxLatency.Round(time.Millisecond))
+}
+
+// outcomeStats counts the outcomes of the transactions of a closed-loop run.
+type outcomeStats struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+func newOutcomeStats() *outcomeStats {
+	return &outcomeStats{
+		counts: make(map[string]uint64),
+	}
+}
+
+func (s *outcomeStats) add(outcome string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counts[outcome]++
+}
+
+func (s *outcomeStats) format() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return formatCounts(s.counts)
+}
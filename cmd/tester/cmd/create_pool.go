@@ -5,9 +5,7 @@ import (
 	"fmt"
 
 	"github.com/b-harvest/cosmos-module-stress-test/client"
-	"github.com/b-harvest/cosmos-module-stress-test/config"
 	"github.com/b-harvest/cosmos-module-stress-test/tx"
-	"github.com/b-harvest/cosmos-module-stress-test/wallet"
 
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 
@@ -48,7 +46,7 @@ func CreatePoolsCmd() *cobra.Command {
 				return err
 			}
 
-			cfg, err := config.Read(config.DefaultConfigPath)
+			cfg, err := ReadConfig()
 			if err != nil {
 				return err
 			}
@@ -64,7 +62,7 @@ func CreatePoolsCmd() *cobra.Command {
 				return err
 			}
 
-			accAddr, privKey, err := wallet.RecoverAccountFromMnemonic(cfg.Custom.Mnemonic, "")
+			master, _, err := recoverAccounts(cfg)
 			if err != nil {
 				return err
 			}
@@ -83,6 +81,17 @@ func CreatePoolsCmd() *cobra.Command {
 				},
 			}
 
+			broadcaster, err := newTxBroadcaster(client, cfg)
+			if err != nil {
+				return err
+			}
+			defer broadcaster.close() // nolint: errcheck
+
+			t, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
+
 			for _, p := range pools {
 				totalNum := 0
 				count := 0
@@ -104,7 +113,7 @@ func CreatePoolsCmd() *cobra.Command {
 							sdktypes.NewCoin(p.denomPairs[j], p.depositCoinB),
 						)
 
-						msg, err := tx.MsgCreatePool(accAddr, p.poolTypeId, depositCoins)
+						msg, err := tx.MsgCreatePool(master.Address, p.poolTypeId, depositCoins)
 						if err != nil {
 							return fmt.Errorf("failed to create msg: %s", err)
 						}
@@ -112,7 +121,7 @@ func CreatePoolsCmd() *cobra.Command {
 					}
 				}
 
-				account, err := client.GRPC.GetBaseAccountInfo(ctx, accAddr)
+				account, err := client.GRPC.GetBaseAccountInfo(ctx, master.Address)
 				if err != nil {
 					return fmt.Errorf("failed to get account information: %s", err)
 				}
@@ -120,35 +129,26 @@ func CreatePoolsCmd() *cobra.Command {
 				accSeq := account.GetSequence()
 				accNum := account.GetAccountNumber()
 
-				gasLimit := uint64(cfg.Custom.GasLimit)
-				fees := sdktypes.NewCoins(sdktypes.NewCoin(cfg.Custom.FeeDenom, sdktypes.NewInt(cfg.Custom.FeeAmount)))
-				memo := cfg.Custom.Memo
-
-				tx := tx.NewTransaction(client, chainID, gasLimit, fees, memo)
-
-				ctx, cancel := context.WithCancel(ctx)
-				defer cancel()
-
-				txBytes, err := tx.Sign(ctx, accSeq, accNum, privKey, msgs...)
+				txBytes, err := t.Sign(ctx, accSeq, accNum, master.Signer, msgs...)
 				if err != nil {
 					return fmt.Errorf("failed to sign and broadcast: %s", err)
 				}
 
-				resp, err := client.GRPC.BroadcastTx(ctx, txBytes)
+				resp, err := broadcaster.broadcast(ctx, master.Address, txBytes)
 				if err != nil {
-					return fmt.Errorf("failed to broadcast transaction: %s", err)
+					return err
 				}
 
 				log.Debug().
 					Str("total messsages", fmt.Sprintf("%d", len(msgs))).
-					Uint32("code", resp.TxResponse.Code).
-					Int64("height", resp.TxResponse.Height).
-					Str("hash", resp.TxResponse.TxHash).
+					Uint32("code", resp.Code).
+					Int64("height", resp.Height).
+					Str("hash", resp.TxHash).
 					Msg("result")
-
-				log.Info().Msgf("reference: %s/cosmos/tx/v1beta1/txs/%s", cfg.LCD.Address, resp.TxResponse.TxHash)
 			}
 
+			broadcaster.log(ctx)
+
 			return nil
 		},
 	}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagChunkSize = "chunk-size"
+)
+
+// FundAccountsCmd distributes coins from the master account to the worker accounts.
+// This command is useful to prepare the worker accounts before a multi-account stress testing.
+func FundAccountsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "fund-accounts [coins]",
+		Short:   "fund every worker account with coins from the master account.",
+		Aliases: []string{"fund", "f"},
+		Args:    cobra.ExactArgs(1),
+		Long: `Fund every worker account derived from the mnemonic with coins from the master account.
+Accounts are topped up to the given coins, so accounts that already hold enough are skipped.
+The coins are distributed with multi send messages of at most chunk-size outputs per transaction.
+
+Example: $ tester fund-accounts 1000000000stake,1000000000uatom --chunk-size 100
+
+[coins]: coins that every worker account should hold
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+			if err != nil {
+				return err
+			}
+			defer client.Stop() // nolint: errcheck
+
+			coins, err := sdktypes.ParseCoinsNormalized(args[0])
+			if err != nil {
+				return err
+			}
+
+			err = coins.Validate()
+			if err != nil {
+				return err
+			}
+
+			chunkSize, err := cmd.Flags().GetInt(flagChunkSize)
+			if err != nil {
+				return err
+			}
+
+			if chunkSize <= 0 {
+				return fmt.Errorf("chunk-size must be positive: %d", chunkSize)
+			}
+
+			chainID, err := client.RPC.GetNetworkChainID(ctx)
+			if err != nil {
+				return err
+			}
+
+			master, accounts, err := recoverAccounts(cfg)
+			if err != nil {
+				return err
+			}
+
+			var outputs []banktypes.Output
+
+			for _, acc := range accounts {
+				if acc.Address == master.Address {
+					continue
+				}
+
+				shortfall := sdktypes.NewCoins()
+				for _, coin := range coins {
+					balance, err := client.GRPC.GetBalance(ctx, acc.Address, coin.Denom)
+					if err != nil {
+						return fmt.Errorf("failed to get balance of %s: %s", acc.Address, err)
+					}
+
+					if balance.Amount.LT(coin.Amount) {
+						shortfall = shortfall.Add(sdktypes.NewCoin(coin.Denom, coin.Amount.Sub(balance.Amount)))
+					}
+				}
+
+				if shortfall.Empty() {
+					log.Debug().Msgf("skipping %s which already holds enough coins", acc.Address)
+					continue
+				}
+
+				accAddr, err := sdktypes.AccAddressFromBech32(acc.Address)
+				if err != nil {
+					return err
+				}
+
+				outputs = append(outputs, banktypes.NewOutput(accAddr, shortfall))
+			}
+
+			if len(outputs) == 0 {
+				log.Info().Msgf("all %d worker accounts already hold %s", len(accounts), coins)
+				return nil
+			}
+
+			// distribute coins in chunks of outputs to keep each transaction within a sane size
+			var msgs []sdktypes.Msg
+			for start := 0; start < len(outputs); start += chunkSize {
+				end := start + chunkSize
+				if end > len(outputs) {
+					end = len(outputs)
+				}
+
+				msg, err := tx.MsgMultiSend(master.Address, outputs[start:end])
+				if err != nil {
+					return fmt.Errorf("failed to create msg: %s", err)
+				}
+				msgs = append(msgs, msg)
+			}
+
+			account, err := client.GRPC.GetBaseAccountInfo(ctx, master.Address)
+			if err != nil {
+				return fmt.Errorf("failed to get account information: %s", err)
+			}
+
+			accSeq := account.GetSequence()
+			accNum := account.GetAccountNumber()
+
+			broadcaster, err := newTxBroadcaster(client, cfg)
+			if err != nil {
+				return err
+			}
+			defer broadcaster.close() // nolint: errcheck
+
+			tx, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
+
+			for i, msg := range msgs {
+				txByte, err := tx.Sign(ctx, accSeq, accNum, master.Signer, msg)
+				if err != nil {
+					return fmt.Errorf("failed to sign and broadcast: %s", err)
+				}
+
+				accSeq = accSeq + 1
+
+				log.Info().Msgf("chunk:%d/%d", i+1, len(msgs))
+
+				_, err = broadcaster.broadcast(ctx, master.Address, txByte)
+				if err != nil {
+					return err
+				}
+			}
+
+			broadcaster.log(ctx)
+
+			return nil
+		},
+	}
+	cmd.Flags().Int(flagChunkSize, 100, "Maximum number of accounts funded in a single transaction.")
+	return cmd
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFactor      = "factor"
+	flagMaxAttempts = "max-attempts"
+)
+
+// GasPriceFloorCmd raises the gas prices of a transaction until the node stops rejecting it for an insufficient fee.
+// This command is useful to find the effective minimum gas prices of a validator.
+func GasPriceFloorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "gas-price-floor [start-gas-prices]",
+		Short:   "raise gas prices until the node accepts the fee of a transaction.",
+		Aliases: []string{"gpf"},
+		Args:    cobra.MaximumNArgs(1),
+		Long: `Broadcast a transaction sending a coin from the master account to itself in the sync mode, so that its CheckTx result is returned.
+While the node rejects it with an insufficient fee error, the gas prices are multiplied by the factor and the transaction is broadcast again.
+The gas prices of the accepted transaction are the effective price floor of the node within a factor.
+
+Example: $ tester gas-price-floor 0.0001stake --factor 1.5
+
+[start-gas-prices]: gas prices to start from. Defaults to gas_prices of the config
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				cfg.Custom.GasPrices = args[0]
+			}
+
+			if cfg.Custom.GasPrices == "" {
+				return fmt.Errorf("start-gas-prices must be given unless gas_prices is set in the config")
+			}
+
+			factorStr, err := cmd.Flags().GetString(flagFactor)
+			if err != nil {
+				return err
+			}
+
+			factor, err := sdktypes.NewDecFromStr(factorStr)
+			if err != nil || !factor.GT(sdktypes.OneDec()) {
+				return fmt.Errorf("factor must be a decimal greater than 1: %s", factorStr)
+			}
+
+			maxAttempts, err := cmd.Flags().GetInt(flagMaxAttempts)
+			if err != nil {
+				return err
+			}
+
+			client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+			if err != nil {
+				return err
+			}
+			defer client.Stop() // nolint: errcheck
+
+			chainID, err := client.RPC.GetNetworkChainID(ctx)
+			if err != nil {
+				return err
+			}
+
+			master, _, err := recoverAccounts(cfg)
+			if err != nil {
+				return err
+			}
+
+			t, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
+
+			if t.GasPrices.IsZero() {
+				return fmt.Errorf("gas prices must be positive: %s", cfg.Custom.GasPrices)
+			}
+
+			// a coin is sent to the master account itself, so that the fee is the only cost of an attempt
+			amount := sdktypes.NewCoins(sdktypes.NewInt64Coin(t.GasPrices[0].Denom, 1))
+			msg, err := tx.MsgSend(master.Address, master.Address, amount)
+			if err != nil {
+				return fmt.Errorf("failed to create msg: %s", err)
+			}
+
+			for i := 0; i < maxAttempts; i++ {
+				account, err := client.GRPC.GetBaseAccountInfo(ctx, master.Address)
+				if err != nil {
+					return fmt.Errorf("failed to get account information: %s", err)
+				}
+
+				txByte, err := t.Sign(ctx, account.GetSequence(), account.GetAccountNumber(), master.Signer, msg)
+				if err != nil {
+					return fmt.Errorf("failed to sign and broadcast: %s", err)
+				}
+
+				resp, err := client.GRPC.BroadcastTxMode(ctx, txByte, sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+				if err != nil {
+					return fmt.Errorf("failed to broadcast transaction: %s", err)
+				}
+
+				gasPrices := t.GasPrices
+				switch {
+				case resp.TxResponse.Code == 0:
+					log.Info().Msgf("accepted at gas prices %s after %d attempts: %s/cosmos/tx/v1beta1/txs/%s", gasPrices, i+1, cfg.LCD.Address, resp.TxResponse.TxHash)
+					return nil
+				case tx.IsInsufficientFee(resp.TxResponse):
+					raised := t.RaiseGasPrices(factor)
+					log.Info().Msgf("rejected at gas prices %s; raising to %s: %s", gasPrices, raised, resp.TxResponse.RawLog)
+				default:
+					return fmt.Errorf("transaction rejected for another reason than the fee: code:%d; log:%s", resp.TxResponse.Code, resp.TxResponse.RawLog)
+				}
+			}
+
+			return fmt.Errorf("node still rejects the fee at gas prices %s after %d attempts", t.GasPrices, maxAttempts)
+		},
+	}
+	cmd.Flags().String(flagFactor, "1.2", "Factor the gas prices are multiplied by after a rejected transaction.")
+	cmd.Flags().Int(flagMaxAttempts, 50, "Maximum number of transactions to broadcast.")
+	return cmd
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/b-harvest/cosmos-module-stress-test/codec"
+	"github.com/b-harvest/cosmos-module-stress-test/config"
+	"github.com/b-harvest/cosmos-module-stress-test/genesis"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagGenesis = "genesis"
+	flagOutput  = "output"
+	flagDerive  = "derive"
+)
+
+// GenesisAccountsCmd generates accounts and adds them to a genesis file with the given balances.
+// This command is useful to bootstrap a localnet with hundreds of funded accounts.
+func GenesisAccountsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "genesis-accounts [num-accounts] [coins]",
+		Short:   "generate accounts to an accounts file and add them to a genesis file.",
+		Aliases: []string{"ga"},
+		Args:    cobra.ExactArgs(2),
+		Long: `Generate accounts, each from a new mnemonic, and write them to a JSON accounts file.
+With the derive flag, the accounts are derived from the configured mnemonic from address_index instead.
+The config is read only with the derive flag; the generated accounts use the default chain settings otherwise.
+When a genesis file is given, the accounts are added to it with the given coins as in add-genesis-account.
+Set accounts_file in the config to use the accounts of the file as worker accounts.
+
+Example: $ tester genesis-accounts 500 1000000000stake,1000000000uatom --genesis ./data/localnet/config/genesis.json
+
+[num-accounts]: how many accounts to generate
+[coins]: coins that every account holds in the genesis
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			derive, err := cmd.Flags().GetBool(flagDerive)
+			if err != nil {
+				return err
+			}
+
+			// the config holds the mnemonic of the derived accounts and is not needed to generate new ones
+			var cfg *config.Config
+			if derive {
+				cfg, err = ReadConfig()
+				if err != nil {
+					return err
+				}
+			} else {
+				var chain *config.ChainConfig
+				err = chain.Apply()
+				if err != nil {
+					return fmt.Errorf("failed to apply chain config: %s", err)
+				}
+			}
+
+			codec.SetCodec()
+
+			numAccounts, err := strconv.ParseUint(args[0], 10, 32)
+			if err != nil || numAccounts == 0 {
+				return fmt.Errorf("num-accounts must be positive integer: %s", args[0])
+			}
+
+			coins, err := sdktypes.ParseCoinsNormalized(args[1])
+			if err != nil {
+				return err
+			}
+
+			err = coins.Validate()
+			if err != nil {
+				return err
+			}
+
+			genFile, err := cmd.Flags().GetString(flagGenesis)
+			if err != nil {
+				return err
+			}
+
+			output, err := cmd.Flags().GetString(flagOutput)
+			if err != nil {
+				return err
+			}
+
+			var keys []wallet.AccountKey
+			if derive {
+				accounts, err := wallet.RecoverAccountsFromMnemonic(cfg.Custom.Mnemonic, "", cfg.Custom.AccountIndex, cfg.Custom.AddressIndex, uint32(numAccounts))
+				if err != nil {
+					return fmt.Errorf("failed to retrieve accounts from mnemonic: %s", err)
+				}
+
+				keys, err = wallet.NewAccountKeys(accounts)
+				if err != nil {
+					return err
+				}
+			} else {
+				keys, err = wallet.GenerateAccountKeys(uint32(numAccounts))
+				if err != nil {
+					return err
+				}
+			}
+
+			err = wallet.WriteAccountsFile(output, keys)
+			if err != nil {
+				return err
+			}
+
+			log.Info().Msgf("wrote %d accounts to %s", len(keys), output)
+
+			if genFile == "" {
+				return nil
+			}
+
+			addresses := make([]string, 0, len(keys))
+			for _, key := range keys {
+				addresses = append(addresses, key.Address)
+			}
+
+			err = genesis.AddGenesisAccounts(genFile, addresses, coins)
+			if err != nil {
+				return err
+			}
+
+			log.Info().Msgf("added %d accounts holding %s to %s", len(addresses), coins, genFile)
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagGenesis, "", "Genesis file to add the accounts to.")
+	cmd.Flags().String(flagOutput, "accounts.json", "Accounts file to write the accounts to.")
+	cmd.Flags().Bool(flagDerive, false, "Derive the accounts from the configured mnemonic instead of generating new mnemonics.")
+	return cmd
+}
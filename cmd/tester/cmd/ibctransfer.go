@@ -7,9 +7,7 @@ import (
 	"strings"
 
 	"github.com/b-harvest/cosmos-module-stress-test/client"
-	"github.com/b-harvest/cosmos-module-stress-test/config"
 	"github.com/b-harvest/cosmos-module-stress-test/tx"
-	"github.com/b-harvest/cosmos-module-stress-test/wallet"
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
@@ -37,7 +35,7 @@ func IBCtransferCmd() *cobra.Command {
 Example: $tester t transfer channel-0 cosmos1pacc0fr45hggcn8jrfhgnqf8vgyqna7r5sftql 10uatom 10 1 1
 
 round: how many rounds to run
-tx-num: how many transactions to be included in a block
+tx-num: how many transactions to be included in a round, which is broadcast right after a block with --block-sync
 msg-num: how many transaction messages to be included in a transaction
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -46,7 +44,7 @@ msg-num: how many transaction messages to be included in a transaction
 				return err
 			}
 
-			cfg, err := config.Read(config.DefaultConfigPath)
+			cfg, err := ReadConfig()
 			if err != nil {
 				return fmt.Errorf("failed to read config file: %s", err)
 			}
@@ -89,8 +87,8 @@ msg-num: how many transaction messages to be included in a transaction
 			}
 
 			txNum, err := strconv.Atoi(args[5])
-			if err != nil {
-				return fmt.Errorf("txNum must be integer: %s", args[0])
+			if err != nil || txNum <= 0 {
+				return fmt.Errorf("tx-num must be positive integer: %s", args[5])
 			}
 
 			msgNum, err := strconv.Atoi(args[6])
@@ -98,59 +96,76 @@ msg-num: how many transaction messages to be included in a transaction
 				return fmt.Errorf("txNum must be integer: %s", args[0])
 			}
 
-			accAddr, privKey, err := wallet.RecoverAccountFromMnemonic(cfg.Custom.Mnemonic, "")
+			_, accounts, err := recoverAccounts(cfg)
 			if err != nil {
-				return fmt.Errorf("failed to retrieve account from mnemonic: %s", err)
+				return err
 			}
 
-			gasLimit := uint64(cfg.Custom.GasLimit)
-			fees := sdktypes.NewCoins(sdktypes.NewCoin(cfg.Custom.FeeDenom, sdktypes.NewInt(cfg.Custom.FeeAmount)))
-			memo := cfg.Custom.Memo
+			workers := newWorkers(accounts, txNum)
+			seqs := tx.NewSequenceManager(client)
 
-			tx := tx.IbcNewtransaction(client, chainID, gasLimit, fees, memo)
+			broadcaster, err := newTxBroadcaster(client, cfg)
+			if err != nil {
+				return err
+			}
+			defer broadcaster.close() // nolint: errcheck
 
-			for i := 0; i < round; i++ {
-				var txBytes [][]byte
+			tx, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
 
-				account, err := client.GRPC.GetBaseAccountInfo(ctx, accAddr)
-				if err != nil {
-					return fmt.Errorf("failed to get account information: %s", err)
-				}
+			maxBytes, err := applyTargetSize(ctx, client, cfg, tx, txNum)
+			if err != nil {
+				return err
+			}
 
-				accSeq := account.GetSequence()
-				accNum := account.GetAccountNumber()
+			report, err := newSizeReport(ctx, client, maxBytes)
+			if err != nil {
+				return err
+			}
 
-				msgs, err := tx.CreateTransferBot(cmd, ibcclientCtx, srcPort, srcChannel, coin, accAddr, receiver, msgNum)
+			blocks, err := newBlockSync(ctx, cmd, client)
+			if err != nil {
+				return err
+			}
 
-				if err != nil {
-					return fmt.Errorf("failed to create msg: %s", err)
+			for i := 0; i < round; i++ {
+				for _, w := range workers {
+					w.msgs, err = tx.CreateTransferBot(cmd, ibcclientCtx, srcPort, srcChannel, coin, w.Address, receiver, msgNum)
+					if err != nil {
+						return fmt.Errorf("failed to create msg: %s", err)
+					}
 				}
 
-				for _, msg := range msgs {
-					fmt.Println("msg: ", msg)
+				txs, err := signRound(ctx, tx, seqs, workers, txNum)
+				if err != nil {
+					return err
 				}
+				report.add(txs)
 
-				for i := 0; i < txNum; i++ {
-					txByte, err := tx.IbcSign(ctx, accSeq, accNum, privKey, msgs...)
-					if err != nil {
-						return fmt.Errorf("failed to sign and broadcast: %s", err)
-					}
-
-					accSeq = accSeq + 1
+				log.Info().Msgf("round:%d; txNum:%d; msgNum: %d; accounts:%d", i+1, txNum, msgNum, len(workers))
 
-					txBytes = append(txBytes, txByte)
+				err = blocks.wait(ctx)
+				if err != nil {
+					return err
 				}
 
-				log.Info().Msgf("round:%d; txNum:%d; msgNum: %d; accAddr:%s", i+1, txNum, msgNum, accAddr)
+				err = broadcastRound(ctx, broadcaster, seqs, txs)
+				if err != nil {
+					return err
+				}
+			}
 
-				for _, txByte := range txBytes {
-					resp, err := client.GRPC.BroadcastTx(ctx, txByte)
-					if err != nil {
-						return fmt.Errorf("failed to broadcast transaction: %s", err)
-					}
+			blocks.log()
+			logSequenceStats(seqs, workers, broadcaster.mode)
+			broadcaster.log(ctx)
+			logSignModes(tx)
+			logGasEstimates(tx)
 
-					log.Info().Msgf("%s/cosmos/tx/v1beta1/txs/%s", cfg.LCD.Address, resp.TxResponse.TxHash)
-				}
+			err = report.log(ctx, client)
+			if err != nil {
+				return err
 			}
 
 			return nil
@@ -160,5 +175,6 @@ msg-num: how many transaction messages to be included in a transaction
 	cmd.Flags().Uint64(flagPacketTimeoutTimestamp, ibctypes.DefaultRelativePacketTimeoutTimestamp, "Packet timeout timestamp in nanoseconds. Default is 10 minutes. The timeout is disabled when set to 0.")
 	cmd.Flags().Bool(flagAbsoluteTimeouts, false, "Timeout flags are used as absolute timeouts.")
 	flags.AddTxFlagsToCmd(cmd)
+	cmd.Flags().Bool(flagBlockSync, false, "Broadcast every round right after a new block is committed and wait for the following block before the next round.")
 	return cmd
 }
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagWeights = "weights"
+
+	// validKind is the kind of the well-formed transactions mixed in with the broken ones.
+	validKind = "valid"
+)
+
+// InvalidCmd broadcasts a mix of valid and deliberately broken transactions and counts the CheckTx codes of every kind.
+// This command is useful to see how nodes handle invalid transactions under load.
+func InvalidCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "invalid [amount] [tx-num]",
+		Short:   "broadcast a mix of valid and broken transactions and count their CheckTx codes by kind.",
+		Aliases: []string{"inv"},
+		Args:    cobra.ExactArgs(2),
+		Long: fmt.Sprintf(`Broadcast tx-num transactions sending the amount from every worker account to itself in the sync mode.
+The kind of every transaction is picked at random by the weights of the kinds, which are valid and the broken kinds:
+%s.
+The CheckTx codes returned by the node are counted for every kind and logged at the end of a run.
+The transactions are always broadcast in the sync mode over the configured gRPC endpoint, so that their CheckTx
+codes are returned; the broadcast mode, transport and policy of the config and the flags are not used.
+
+Example: $ tester invalid 1stake 1000 --weights valid=4,bad-signature=1,truncated=1
+
+[amount]: coins every transaction sends
+[tx-num]: how many transactions to broadcast
+`, strings.Join(invalidKindNames(), ", ")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			amount, err := sdktypes.ParseCoinsNormalized(args[0])
+			if err != nil {
+				return err
+			}
+
+			txNum, err := strconv.Atoi(args[1])
+			if err != nil || txNum <= 0 {
+				return fmt.Errorf("tx-num must be positive integer: %s", args[1])
+			}
+
+			weightsStr, err := cmd.Flags().GetString(flagWeights)
+			if err != nil {
+				return err
+			}
+
+			kinds, weights, err := parseWeights(weightsStr, append([]string{validKind}, invalidKindNames()...))
+			if err != nil {
+				return err
+			}
+
+			client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+			if err != nil {
+				return err
+			}
+			defer client.Stop() // nolint: errcheck
+
+			chainID, err := client.RPC.GetNetworkChainID(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, accounts, err := recoverAccounts(cfg)
+			if err != nil {
+				return err
+			}
+
+			workers := newWorkers(accounts, txNum)
+			for _, w := range workers {
+				msg, err := tx.MsgSend(w.Address, w.Address, amount)
+				if err != nil {
+					return fmt.Errorf("failed to create msg: %s", err)
+				}
+				w.msgs = []sdktypes.Msg{msg}
+			}
+
+			seqs := tx.NewSequenceManager(client)
+
+			t, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
+
+			// the transactions of a worker are broadcast one by one, so that a valid one passes CheckTx before the next
+			plans := make([][]string, len(workers))
+			for i := 0; i < txNum; i++ {
+				plans[i%len(workers)] = append(plans[i%len(workers)], pickKind(kinds, weights))
+			}
+
+			stats := newCodeStats()
+
+			var wg sync.WaitGroup
+			errs := make([]error, len(workers))
+			for i, w := range workers {
+				wg.Add(1)
+				go func(i int, w *worker) {
+					defer wg.Done()
+
+					for _, kind := range plans[i] {
+						err := broadcastKind(ctx, client, t, seqs, w, kind, stats)
+						if err != nil {
+							errs[i] = err
+							return
+						}
+					}
+				}(i, w)
+			}
+			wg.Wait()
+
+			for _, err := range errs {
+				if err != nil {
+					return err
+				}
+			}
+
+			stats.log(kinds)
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagWeights, defaultWeights(), "Comma separated weights of the kinds of transactions.")
+	return cmd
+}
+
+// broadcastKind signs a transaction of the kind with the next sequence of the worker, broadcasts it in the sync mode
+// and counts its CheckTx code. The sequence is resynced when the transaction is rejected, so a broken transaction
+// does not break the valid transactions after it.
+func broadcastKind(ctx context.Context, c *client.Client, t *tx.Transaction, seqs *tx.SequenceManager, w *worker, kind string, stats *codeStats) error {
+	accNum, accSeq, err := seqs.Next(ctx, w.Address)
+	if err != nil {
+		return err
+	}
+
+	var txByte []byte
+	if kind == validKind {
+		txByte, err = t.Sign(ctx, accSeq, accNum, w.Signer, w.msgs...)
+	} else {
+		txByte, err = t.SignInvalid(ctx, tx.InvalidKind(kind), accSeq, accNum, w.Signer, w.msgs...)
+	}
+	if err != nil {
+		seqs.Release(w.Address, accSeq)
+		log.Debug().Msgf("skipping %s tx of %s: %s", kind, w.Address, err)
+		stats.add(kind, "skipped")
+		return nil
+	}
+
+	resp, err := c.GRPC.BroadcastTxMode(ctx, txByte, sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+	if err != nil {
+		seqs.Release(w.Address, accSeq)
+		log.Debug().Msgf("failed to broadcast %s tx of %s: %s", kind, w.Address, err)
+		stats.add(kind, "broadcast-error")
+		return nil
+	}
+
+	seqs.Report(w.Address, accSeq, resp.TxResponse)
+
+	code := "ok"
+	if resp.TxResponse.Code != 0 {
+		code = fmt.Sprintf("%s/%d", resp.TxResponse.Codespace, resp.TxResponse.Code)
+		log.Debug().Msgf("%s tx of %s rejected: code:%s; log:%s", kind, w.Address, code, resp.TxResponse.RawLog)
+	}
+	stats.add(kind, code)
+
+	return nil
+}
+
+// codeStats counts the CheckTx codes of the transactions of every kind.
+type codeStats struct {
+	mu    sync.Mutex
+	codes map[string]map[string]uint64
+}
+
+func newCodeStats() *codeStats {
+	return &codeStats{
+		codes: make(map[string]map[string]uint64),
+	}
+}
+
+func (s *codeStats) add(kind string, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.codes[kind] == nil {
+		s.codes[kind] = make(map[string]uint64)
+	}
+	s.codes[kind][code]++
+}
+
+// log logs the number of transactions of every kind by code.
+func (s *codeStats) log(kinds []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, kind := range kinds {
+		var total uint64
+		codes := make([]string, 0, len(s.codes[kind]))
+		for code, count := range s.codes[kind] {
+			total += count
+			codes = append(codes, fmt.Sprintf("%s=%d", code, count))
+		}
+		sort.Strings(codes)
+
+		log.Info().Msgf("kind:%s; txs:%d; codes:%s", kind, total, strings.Join(codes, " "))
+	}
+}
+
+// invalidKindNames returns the names of the broken kinds.
+func invalidKindNames() []string {
+	names := make([]string, 0, len(tx.InvalidKinds))
+	for _, kind := range tx.InvalidKinds {
+		names = append(names, string(kind))
+	}
+
+	return names
+}
+
+// defaultWeights returns the weights of the valid kind and every broken kind of 1.
+func defaultWeights() string {
+	weights := []string{validKind + "=1"}
+	for _, name := range invalidKindNames() {
+		weights = append(weights, name+"=1")
+	}
+
+	return strings.Join(weights, ",")
+}
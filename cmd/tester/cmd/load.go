@@ -0,0 +1,676 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/load"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	ibctypes "github.com/cosmos/cosmos-sdk/x/ibc/applications/transfer/types"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagDuration = "duration"
+	flagCount    = "count"
+	flagBurst    = "burst"
+	flagRefresh  = "refresh"
+	flagProfile  = "profile"
+)
+
+// loadMsgs builds the messages of a transaction of the account in a load run.
+type loadMsgs func(ctx context.Context, c *client.Client, t *tx.Transaction, address string) ([]sdktypes.Msg, error)
+
+// loadLane is the accounts a worker of a load run signs transactions with in turn.
+// The messages of an account are rebuilt and its sequence is refreshed once the refresh interval passed.
+type loadLane struct {
+	workers   []*worker
+	refreshed []time.Time
+	next      int
+}
+
+// LoadCmd broadcasts transactions open-loop at a target rate for a duration or a number of transactions.
+// This command is useful to hold a steady load on a chain, e.g. 200 transactions per second for 10 minutes.
+func LoadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "load",
+		Short:   "broadcast swap, deposit, withdraw or transfer transactions at a target rate.",
+		Aliases: []string{"l"},
+		Long: `Broadcast transactions at a target rate of transactions per second until the duration passed or
+the count of transactions was broadcast, whichever comes first.
+
+The transactions are scheduled by a token bucket from the start of the run and spread over the workers in turn.
+Every account is owned by one of the workers, which signs and broadcasts the transactions of its accounts in order.
+The schedule does not wait for the workers: when they fall behind, the scheduled transactions queue up and
+their latency is measured from the time they were scheduled at, so a slow node shows up as latency and pending
+transactions rather than as a lower rate.
+
+Instead of a constant rate, the run can follow a profile of steps of the target rate, which ends the run when
+it ends unless the duration or the count ends it first. The profiles are:
+
+  ramp:from=10,to=500,duration=10m[,steps=10]     rises linearly from a rate to a rate in a number of steps
+  stairs:from=50,to=500,step=50,hold=1m            holds every rate from a rate to a rate by the step
+  spike:base=50,peak=1000,every=1m,length=5s[,count=1]
+                                                   holds the base and bursts to the peak for the length once
+                                                   every period, and holds the base once more to recover
+  sine:base=200,amplitude=150,period=2m,duration=10m[,steps=8]
+                                                   follows a sine wave around the base in a number of steps
+                                                   per period
+
+The achieved rate, the target rate and the latencies are reported every second. The response of every transaction
+is logged at the debug level, so run with --log-level info to see the reports alone.
+With --closed-loop, the run is closed-loop instead: every worker owns one account and sends a transaction,
+waits until it is committed or rejected by CheckTx and only then sends the next one. The concurrency is the number
+of workers, which overrides num_accounts of the config, and the rate and the profile are not used. The throughput
+and the end-to-end latencies are reported every second, and the transactions are broadcast in the sync mode unless
+the block mode is configured.
+
+After an open-loop run, the blocks committed during the run are scanned and every step is reported with the rate its
+transactions were broadcast and included in blocks at, and their broadcast and inclusion latencies, so the rate at
+which the inclusion rate stops following the target rate can be read off the steps.
+
+Example: $ tester load swap 1 1000000uatom uusd 2 --rate 200 --duration 10m
+Example: $ tester load deposit 1 1000000uatom,1000000uusd --profile stairs:from=50,to=500,step=50,hold=1m
+Example: $ tester load swap 1 1000000uatom uusd 1 --closed-loop --concurrency 50 --duration 5m
+`,
+	}
+	cmd.PersistentFlags().Float64(flagRate, 10, "Target transactions per second.")
+	cmd.PersistentFlags().Duration(flagDuration, 0, "Duration of the run. The run is not limited by time when it is 0.")
+	cmd.PersistentFlags().Int(flagCount, 0, "Number of transactions of the run. The run is not limited by count when it is 0.")
+	cmd.PersistentFlags().Int(flagBurst, 0, "Number of transactions broadcast at once to catch up with the schedule. Defaults to one second of transactions.")
+	cmd.PersistentFlags().Int(flagConcurrency, 0, "Number of concurrent workers. Defaults to one worker per account; in the closed-loop mode, one account is derived for every worker.")
+	cmd.PersistentFlags().Duration(flagRefresh, 10*time.Second, "Interval the messages of an account are rebuilt and its sequence is refreshed at.")
+	cmd.PersistentFlags().String(flagProfile, "", "Profile of the target rate the run follows instead of the rate, e.g. ramp:from=10,to=500,duration=10m.")
+	cmd.PersistentFlags().Int64(flagWaitBlocks, 2, "Number of blocks to wait for the transactions to be included after the run.")
+	cmd.PersistentFlags().Bool(flagClosedLoop, false, "Every worker sends its next transaction only after the previous one is committed or rejected.")
+	cmd.PersistentFlags().Duration(flagCommitTimeout, 30*time.Second, "Time a worker waits for its transaction to be committed in the closed-loop mode.")
+	cmd.PersistentFlags().Duration(flagPollInterval, 200*time.Millisecond, "Interval a worker polls for its transaction to be committed at in the closed-loop mode.")
+
+	cmd.AddCommand(loadSwapCmd())
+	cmd.AddCommand(loadDepositCmd())
+	cmd.AddCommand(loadWithdrawCmd())
+	cmd.AddCommand(loadTransferCmd())
+
+	return cmd
+}
+
+func loadSwapCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "swap [pool-id] [offer-coin] [demand-coin-denom] [msg-num]",
+		Short: "swap offer coin with demand coin at a target rate.",
+		Args:  cobra.ExactArgs(4),
+		Long: `Swap offer coin with demand coin from the liquidity pool at a target rate.
+The order prices follow the pool price, which is queried whenever the messages of an account are rebuilt.
+
+Example: $ tester load swap 1 5000000ubtsg uatom 2 --rate 200 --duration 10m
+
+[msg-num]: how many transaction messages to be included in a transaction
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			poolId, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("pool-id %s not a valid uint, input a valid unsigned 32-bit integer for pool-id", args[0])
+			}
+
+			offerCoin, err := sdktypes.ParseCoinNormalized(args[1])
+			if err != nil {
+				return err
+			}
+
+			err = sdktypes.ValidateDenom(args[2])
+			if err != nil {
+				return err
+			}
+
+			msgNum, err := strconv.Atoi(args[3])
+			if err != nil || msgNum <= 0 {
+				return fmt.Errorf("msg-num must be positive integer: %s", args[3])
+			}
+
+			return runLoad(cmd, func(ctx context.Context, c *client.Client, t *tx.Transaction, address string) ([]sdktypes.Msg, error) {
+				return t.CreateSwapBot(ctx, address, poolId, offerCoin, args[2], msgNum)
+			})
+		},
+	}
+}
+
+func loadDepositCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deposit [pool-id] [deposit-coins]",
+		Short: "deposit coins to a liquidity pool at a target rate.",
+		Args:  cobra.ExactArgs(2),
+		Long: `Deposit coins to a liquidity pool at a target rate.
+
+Example: $ tester load deposit 1 100000000uatom,5000000000uusd --rate 50 --count 10000
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			poolId, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("pool-id %s not a valid uint, input a valid unsigned 32-bit integer for pool-id", args[0])
+			}
+
+			depositCoins, err := sdktypes.ParseCoinsNormalized(args[1])
+			if err != nil {
+				return err
+			}
+
+			if depositCoins.Len() != 2 {
+				return fmt.Errorf("the number of deposit coins must be two in the pool-type 1")
+			}
+
+			return runLoad(cmd, func(ctx context.Context, c *client.Client, t *tx.Transaction, address string) ([]sdktypes.Msg, error) {
+				msg, err := tx.MsgDeposit(address, poolId, depositCoins)
+				if err != nil {
+					return nil, err
+				}
+
+				return []sdktypes.Msg{msg}, nil
+			})
+		},
+	}
+}
+
+func loadWithdrawCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "withdraw [pool-id] [pool-coin]",
+		Short: "withdraw pool coin from the pool at a target rate.",
+		Args:  cobra.ExactArgs(2),
+		Long: `Withdraw pool coin from the pool at a target rate.
+
+Example: $ tester load withdraw 1 10pool94720F40B38D6DD93DCE184D264D4BE089EDF124A9C0658CDBED6CA18CF27752 --rate 50 --duration 1m
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			poolId, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("pool-id %s not a valid uint, input a valid unsigned 32-bit integer for pool-id", args[0])
+			}
+
+			poolCoin, err := sdktypes.ParseCoinNormalized(args[1])
+			if err != nil {
+				return err
+			}
+
+			return runLoad(cmd, func(ctx context.Context, c *client.Client, t *tx.Transaction, address string) ([]sdktypes.Msg, error) {
+				msg, err := tx.MsgWithdraw(address, poolId, poolCoin)
+				if err != nil {
+					return nil, err
+				}
+
+				return []sdktypes.Msg{msg}, nil
+			})
+		},
+	}
+}
+
+func loadTransferCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer [src-port] [src-channel] [receiver] [amount] [msg-num]",
+		Short: "transfer a fungible token through IBC at a target rate.",
+		Args:  cobra.ExactArgs(5),
+		Long: `Transfer a fungible token through IBC at a target rate.
+The packet timeouts are relative to the latest consensus state of the channel when the messages of an account
+are rebuilt unless absolute timeouts are used.
+
+Example: $ tester load transfer transfer channel-0 cosmos1pacc0fr45hggcn8jrfhgnqf8vgyqna7r5sftql 10uatom 1 --rate 100 --duration 5m
+
+[msg-num]: how many transaction messages to be included in a transaction
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			srcPort := args[0]
+			srcChannel := args[1]
+			receiver := args[2]
+
+			coin, err := sdktypes.ParseCoinNormalized(args[3])
+			if err != nil {
+				return err
+			}
+
+			if !strings.HasPrefix(coin.Denom, "ibc/") {
+				denomTrace := ibctypes.ParseDenomTrace(coin.Denom)
+				coin.Denom = denomTrace.IBCDenom()
+			}
+
+			msgNum, err := strconv.Atoi(args[4])
+			if err != nil || msgNum <= 0 {
+				return fmt.Errorf("msg-num must be positive integer: %s", args[4])
+			}
+
+			return runLoad(cmd, func(ctx context.Context, c *client.Client, t *tx.Transaction, address string) ([]sdktypes.Msg, error) {
+				return t.CreateTransferBot(cmd, c.GetCLIContext(), srcPort, srcChannel, coin, address, receiver, msgNum)
+			})
+		},
+	}
+	cmd.Flags().String(flagPacketTimeoutHeight, ibctypes.DefaultRelativePacketTimeoutHeight, "Packet timeout block height. The timeout is disabled when set to 0-0.")
+	cmd.Flags().Uint64(flagPacketTimeoutTimestamp, ibctypes.DefaultRelativePacketTimeoutTimestamp, "Packet timeout timestamp in nanoseconds. Default is 10 minutes. The timeout is disabled when set to 0.")
+	cmd.Flags().Bool(flagAbsoluteTimeouts, false, "Timeout flags are used as absolute timeouts.")
+	return cmd
+}
+
+// runLoad runs a load of the transactions of the messages built by build with the rate and the stop conditions
+// of the flags of the load command, and logs the results of the run when it ends or is interrupted.
+// The run is closed-loop when the closed-loop flag is set.
+func runLoad(cmd *cobra.Command, build loadMsgs) error {
+	closedLoop, err := cmd.Flags().GetBool(flagClosedLoop)
+	if err != nil {
+		return err
+	}
+
+	if closedLoop {
+		return runClosedLoad(cmd, build)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	err = SetLogger(logLevel)
+	if err != nil {
+		return err
+	}
+
+	loadCfg, refresh, err := parseLoadFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	waitBlocks, err := cmd.Flags().GetInt64(flagWaitBlocks)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+	if err != nil {
+		return err
+	}
+	defer client.Stop() // nolint: errcheck
+
+	chainID, err := client.RPC.GetNetworkChainID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, accounts, err := recoverAccounts(cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(accounts) == 0 {
+		return fmt.Errorf("no accounts to broadcast transactions with")
+	}
+
+	workers := newWorkers(accounts, len(accounts))
+	if loadCfg.Workers == 0 || loadCfg.Workers > len(workers) {
+		loadCfg.Workers = len(workers)
+	}
+
+	lanes := make([]*loadLane, loadCfg.Workers)
+	for i := range lanes {
+		lanes[i] = &loadLane{}
+	}
+	for i, w := range workers {
+		lane := lanes[i%len(lanes)]
+		lane.workers = append(lane.workers, w)
+		lane.refreshed = append(lane.refreshed, time.Time{})
+	}
+
+	seqs := tx.NewSequenceManager(client)
+
+	broadcaster, err := newTxBroadcaster(client, cfg)
+	if err != nil {
+		return err
+	}
+	defer broadcaster.close() // nolint: errcheck
+	broadcaster.txLogLevel = zerolog.DebugLevel
+
+	t, err := newTransaction(client, chainID, cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(loadCfg.Profile) > 0 {
+		log.Info().Msgf("profile:%s; steps:%d; duration:%s; count:%d; workers:%d; accounts:%d; broadcastMode:%s; transport:%s; policy:%s",
+			cmd.Flag(flagProfile).Value, len(loadCfg.Profile), loadCfg.Profile.Duration(), loadCfg.Count, loadCfg.Workers, len(workers),
+			broadcaster.modeName, broadcaster.transport, broadcaster.policy)
+	} else {
+		log.Info().Msgf("rate:%.2f; duration:%s; count:%d; workers:%d; accounts:%d; broadcastMode:%s; transport:%s; policy:%s",
+			loadCfg.Rate, loadCfg.Duration, loadCfg.Count, loadCfg.Workers, len(workers), broadcaster.modeName, broadcaster.transport, broadcaster.policy)
+	}
+
+	startHeight, err := client.RPC.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	inclusions := newInclusionTracker()
+
+	// the blocks are observed until the blocks after the run are committed, even when the run is interrupted
+	observeCtx, stopObserving := context.WithCancel(context.Background())
+	defer stopObserving()
+
+	err = inclusions.observe(observeCtx, client)
+	if err != nil {
+		return err
+	}
+
+	op := func(ctx context.Context, job load.Job) error {
+		lane := lanes[job.Worker]
+		k := lane.next
+		lane.next = (lane.next + 1) % len(lane.workers)
+		w := lane.workers[k]
+
+		if time.Since(lane.refreshed[k]) >= refresh {
+			err := seqs.Refresh(ctx, w.Address)
+			if err != nil {
+				log.Debug().Msgf("failed to refresh sequence of %s: %s", w.Address, err)
+				return err
+			}
+
+			w.msgs, err = build(ctx, client, t, w.Address)
+			if err != nil {
+				log.Debug().Msgf("failed to create msg of %s: %s", w.Address, err)
+				return err
+			}
+			lane.refreshed[k] = time.Now()
+		}
+
+		resp, err := signAndBroadcastNext(ctx, broadcaster, t, seqs, w)
+		if err != nil {
+			log.Debug().Msgf("tx of %s failed: %s", w.Address, err)
+			return err
+		}
+		inclusions.track(resp.TxHash, job)
+
+		return nil
+	}
+
+	result, err := load.Run(ctx, loadCfg, op, logLoadReport)
+	if err != nil {
+		return err
+	}
+
+	// the run may be interrupted, but the transactions broadcast are still counted in the blocks
+	ctx = context.Background()
+
+	err = waitForBlocks(ctx, client, waitBlocks)
+	if err != nil {
+		return err
+	}
+
+	stopObserving()
+
+	err = inclusions.scan(ctx, client, startHeight+1)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range result.Steps {
+		logLoadStep(step, inclusions.stats(step.Step))
+	}
+
+	total := result.Total
+	log.Info().Msgf("total; elapsed:%s; target:%.2f; achieved:%.2f; scheduled:%d; completed:%d; failed:%d; late:%d; dropped:%d; included:%d; avgLatency:%s; maxLatency:%s",
+		total.Elapsed.Round(time.Millisecond), total.Target, total.Rate(), total.Scheduled, total.Completed, total.Failed, total.Late, total.Dropped, inclusions.included(),
+		total.AvgLatency.Round(time.Microsecond), total.MaxLatency.Round(time.Microsecond))
+
+	logSequenceStats(seqs, workers, broadcaster.mode)
+	broadcaster.log(context.Background())
+	logSignModes(t)
+	logGasEstimates(t)
+
+	return nil
+}
+
+// parseLoadFlags returns the config of a load run and the refresh interval of the flags of the load command.
+// The workers are left 0 when the concurrency flag is not set.
+func parseLoadFlags(cmd *cobra.Command) (load.Config, time.Duration, error) {
+	rate, err := cmd.Flags().GetFloat64(flagRate)
+	if err != nil {
+		return load.Config{}, 0, err
+	}
+
+	duration, err := cmd.Flags().GetDuration(flagDuration)
+	if err != nil {
+		return load.Config{}, 0, err
+	}
+
+	count, err := cmd.Flags().GetInt(flagCount)
+	if err != nil {
+		return load.Config{}, 0, err
+	}
+
+	burst, err := cmd.Flags().GetInt(flagBurst)
+	if err != nil {
+		return load.Config{}, 0, err
+	}
+
+	concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+	if err != nil {
+		return load.Config{}, 0, err
+	}
+
+	if concurrency < 0 {
+		return load.Config{}, 0, fmt.Errorf("concurrency must not be negative: %d", concurrency)
+	}
+
+	refresh, err := cmd.Flags().GetDuration(flagRefresh)
+	if err != nil {
+		return load.Config{}, 0, err
+	}
+
+	profileSpec, err := cmd.Flags().GetString(flagProfile)
+	if err != nil {
+		return load.Config{}, 0, err
+	}
+
+	var profile load.Profile
+	if profileSpec != "" {
+		profile, err = load.ParseProfile(profileSpec)
+		if err != nil {
+			return load.Config{}, 0, err
+		}
+	}
+
+	cfg := load.Config{
+		Profile:  profile,
+		Rate:     rate,
+		Burst:    burst,
+		Duration: duration,
+		Count:    count,
+		Workers:  concurrency,
+	}
+
+	// the workers are validated once the number of accounts is known
+	validated := cfg
+	validated.Workers = 1
+	if err := validated.Validate(); err != nil {
+		return load.Config{}, 0, err
+	}
+
+	return cfg, refresh, nil
+}
+
+// signAndBroadcastNext signs the messages of the worker with its next sequence, broadcasts the transaction and
+// returns its response. It returns an error when the transaction failed to be broadcast or was rejected.
+func signAndBroadcastNext(ctx context.Context, b *txBroadcaster, t *tx.Transaction, seqs *tx.SequenceManager, w *worker) (*sdktypes.TxResponse, error) {
+	accNum, accSeq, err := seqs.Next(ctx, w.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	txBytes, err := t.Sign(ctx, accSeq, accNum, w.Signer, w.msgs...)
+	if err != nil {
+		seqs.Release(w.Address, accSeq)
+		return nil, fmt.Errorf("failed to sign transaction: %s", err)
+	}
+
+	resp, err := b.broadcast(ctx, w.Address, txBytes)
+	if err != nil {
+		seqs.Release(w.Address, accSeq)
+		return nil, err
+	}
+
+	if seqs.Report(w.Address, accSeq, resp) {
+		log.Warn().Msgf("resynced sequence of %s after rejected tx: code:%d; log:%s", w.Address, resp.Code, resp.RawLog)
+	}
+
+	if resp.Code != 0 {
+		return resp, fmt.Errorf("tx of %s rejected: code:%s/%d; log:%s", w.Address, resp.Codespace, resp.Code, resp.RawLog)
+	}
+
+	return resp, nil
+}
+
+// logLoadReport logs the achieved rate against the target rate of a second of a load run.
+func logLoadReport(r load.Report) {
+	log.Info().Msgf("elapsed:%s; step:%d; target:%.2f; achieved:%.2f; scheduled:%d; completed:%d; failed:%d; late:%d; dropped:%d; pending:%d; avgLatency:%s; maxLatency:%s",
+		r.Elapsed.Round(time.Second), r.Step+1, r.Target, r.Rate(), r.Scheduled, r.Completed, r.Failed, r.Late, r.Dropped, r.Pending,
+		r.AvgLatency.Round(time.Microsecond), r.MaxLatency.Round(time.Microsecond))
+}
+
+// logLoadStep logs the rates the transactions of a step of a load run were broadcast and included in blocks at
+// against the target rate of the step, with their broadcast and inclusion latencies.
+func logLoadStep(r load.Report, s inclusionStats) {
+	log.Info().Msgf("step:%d; until:%s; target:%.2f; achieved:%.2f; included:%d; inclusionRate:%.2f; failed:%d; late:%d; dropped:%d; avgLatency:%s; maxLatency:%s; avgInclusionLatency:%s; maxInclusionLatency:%s",
+		r.Step+1, r.Elapsed.Round(time.Second), r.Target, r.Rate(), s.included, float64(s.included)/r.Interval.Seconds(), r.Failed, r.Late, r.Dropped,
+		r.AvgLatency.Round(time.Microsecond), r.MaxLatency.Round(time.Microsecond),
+		s.avgLatency().Round(time.Millisecond), s.maxLatency.Round(time.Millisecond))
+}
+
+// inclusionStats counts the transactions of a step of a load run included in blocks and their inclusion latencies,
+// which are measured from the time a transaction was scheduled at to the local time its block was observed at,
+// rather than the BFT time of the block, which lags behind the commit and is skewed against the local clock.
+type inclusionStats struct {
+	included     int
+	measured     int // included transactions whose block was observed
+	totalLatency time.Duration
+	maxLatency   time.Duration
+}
+
+func (s inclusionStats) avgLatency() time.Duration {
+	if s.measured == 0 {
+		return 0
+	}
+
+	return s.totalLatency / time.Duration(s.measured)
+}
+
+// inclusionTracker records the transactions broadcast in a load run by their hashes and the local time every block
+// is observed at, and counts the ones included in blocks by the step they were scheduled in.
+type inclusionTracker struct {
+	mu       sync.Mutex
+	txs      map[string]load.Job
+	observed map[int64]time.Time
+	steps    map[int]inclusionStats
+}
+
+func newInclusionTracker() *inclusionTracker {
+	return &inclusionTracker{
+		txs:      make(map[string]load.Job),
+		observed: make(map[int64]time.Time),
+		steps:    make(map[int]inclusionStats),
+	}
+}
+
+// observe records the local time the header of every new block is received at until the context is done.
+func (it *inclusionTracker) observe(ctx context.Context, c *client.Client) error {
+	headers, err := c.RPC.SubscribeNewBlockHeaders(ctx, "inclusion-tracker")
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for header := range headers {
+			it.mu.Lock()
+			it.observed[header.Height] = time.Now()
+			it.mu.Unlock()
+		}
+	}()
+
+	return nil
+}
+
+// track records the transaction of the hash broadcast for the job.
+func (it *inclusionTracker) track(hash string, job load.Job) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	it.txs[strings.ToUpper(hash)] = job
+}
+
+// scan counts the tracked transactions executed successfully in the blocks from the height to the latest block.
+func (it *inclusionTracker) scan(ctx context.Context, c *client.Client, from int64) error {
+	to, err := c.RPC.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	for height := from; height <= to; height++ {
+		block, results, err := c.RPC.GetBlockWithResults(ctx, height)
+		if err != nil {
+			return err
+		}
+
+		observed, measured := it.observed[height]
+
+		for i, txBytes := range block.Txs {
+			job, ok := it.txs[tx.TxHash(txBytes)]
+			if !ok || i >= len(results) || results[i].Code != 0 {
+				continue
+			}
+
+			stats := it.steps[job.Step]
+			stats.included++
+			if measured {
+				latency := observed.Sub(job.Scheduled)
+				stats.measured++
+				stats.totalLatency += latency
+				if latency > stats.maxLatency {
+					stats.maxLatency = latency
+				}
+			}
+			it.steps[job.Step] = stats
+		}
+	}
+
+	return nil
+}
+
+// stats returns the inclusion stats of the step.
+func (it *inclusionTracker) stats(step int) inclusionStats {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	return it.steps[step]
+}
+
+// included returns the number of the tracked transactions included in blocks.
+func (it *inclusionTracker) included() int {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	var included int
+	for _, stats := range it.steps {
+		included += stats.included
+	}
+
+	return included
+}
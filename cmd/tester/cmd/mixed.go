@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// MixedCmd broadcasts transactions that combine swap, deposit and withdraw messages across several pools.
+// This command is useful to see how the batch handlers of the liquidity module interact within a single DeliverTx.
+func MixedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "mixed [pool-ids] [amount] [round] [tx-num] [msg-num]",
+		Short:   "swap, deposit and withdraw across pools with mixed messages in every transaction.",
+		Aliases: []string{"m"},
+		Args:    cobra.ExactArgs(5),
+		Long: `Swap, deposit and withdraw across the pools in round times with a number of tx and msg messages.
+The kind of every message is picked at random by the weights of the kinds, which are swap, deposit and withdraw.
+The messages of a transaction go to the pools in turn. A swap offers the amount of one reserve coin of the pool
+at the pool price, a deposit deposits the amount of both reserve coins and a withdrawal withdraws the amount of
+the pool coin, so the accounts must hold pool coins of the pools to withdraw.
+
+Example: $ tester mixed 1,2,3 1000000 5 5 6 --weights swap=2,deposit=1,withdraw=1
+
+[pool-ids]: comma separated ids of the pools
+[amount]: amount of the coin every message moves
+[round]: how many rounds to run
+[tx-num]: how many transactions to be included in a round, which is broadcast right after a block with --block-sync
+[msg-num]: how many transaction messages to be included in a transaction
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			var poolIds []uint64
+			for _, s := range strings.Split(args[0], ",") {
+				poolId, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+				if err != nil {
+					return fmt.Errorf("pool-id %s not a valid uint, input a valid unsigned 32-bit integer for pool-id", s)
+				}
+				poolIds = append(poolIds, poolId)
+			}
+
+			amount, ok := sdktypes.NewIntFromString(args[1])
+			if !ok || !amount.IsPositive() {
+				return fmt.Errorf("amount must be positive integer: %s", args[1])
+			}
+
+			round, err := strconv.Atoi(args[2])
+			if err != nil {
+				return fmt.Errorf("round must be integer: %s", args[2])
+			}
+
+			txNum, err := strconv.Atoi(args[3])
+			if err != nil || txNum <= 0 {
+				return fmt.Errorf("tx-num must be positive integer: %s", args[3])
+			}
+
+			msgNum, err := strconv.Atoi(args[4])
+			if err != nil || msgNum <= 0 {
+				return fmt.Errorf("msg-num must be positive integer: %s", args[4])
+			}
+
+			weightsStr, err := cmd.Flags().GetString(flagWeights)
+			if err != nil {
+				return err
+			}
+
+			kinds, weights, err := parseWeights(weightsStr, mixedMsgKindNames())
+			if err != nil {
+				return err
+			}
+
+			client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+			if err != nil {
+				return err
+			}
+			defer client.Stop() // nolint: errcheck
+
+			chainID, err := client.RPC.GetNetworkChainID(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, accounts, err := recoverAccounts(cfg)
+			if err != nil {
+				return err
+			}
+
+			workers := newWorkers(accounts, txNum)
+			seqs := tx.NewSequenceManager(client)
+
+			broadcaster, err := newTxBroadcaster(client, cfg)
+			if err != nil {
+				return err
+			}
+			defer broadcaster.close() // nolint: errcheck
+
+			t, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
+
+			maxBytes, err := applyTargetSize(ctx, client, cfg, t, txNum)
+			if err != nil {
+				return err
+			}
+
+			report, err := newSizeReport(ctx, client, maxBytes)
+			if err != nil {
+				return err
+			}
+
+			counts := make(map[string]int)
+			blocks, err := newBlockSync(ctx, cmd, client)
+			if err != nil {
+				return err
+			}
+
+			for i := 0; i < round; i++ {
+				// the pools are queried every round to swap at their current prices
+				pools, err := t.GetMixedPools(ctx, poolIds)
+				if err != nil {
+					return err
+				}
+
+				for _, w := range workers {
+					msgKinds := make([]tx.MixedMsgKind, 0, msgNum)
+					for j := 0; j < msgNum; j++ {
+						kind := pickKind(kinds, weights)
+						msgKinds = append(msgKinds, tx.MixedMsgKind(kind))
+						counts[kind]++
+					}
+
+					w.msgs, err = tx.MixedMsgs(w.Address, pools, msgKinds, amount)
+					if err != nil {
+						return fmt.Errorf("failed to create msg: %s", err)
+					}
+				}
+
+				txs, err := signRound(ctx, t, seqs, workers, txNum)
+				if err != nil {
+					return err
+				}
+				report.add(txs)
+
+				log.Info().Msgf("round:%d; txNum:%d; msgNum: %d; accounts:%d; pools:%d", i+1, txNum, msgNum, len(workers), len(pools))
+
+				err = blocks.wait(ctx)
+				if err != nil {
+					return err
+				}
+
+				err = broadcastRound(ctx, broadcaster, seqs, txs)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, kind := range kinds {
+				log.Info().Msgf("kind:%s; msgs:%d", kind, counts[kind])
+			}
+
+			blocks.log()
+			logSequenceStats(seqs, workers, broadcaster.mode)
+			broadcaster.log(ctx)
+			logSignModes(t)
+			logGasEstimates(t)
+
+			err = report.log(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagWeights, defaultMixedWeights(), "Comma separated weights of the kinds of messages.")
+	cmd.Flags().Bool(flagBlockSync, false, "Broadcast every round right after a new block is committed and wait for the following block before the next round.")
+	return cmd
+}
+
+// mixedMsgKindNames returns the names of the kinds of liquidity messages.
+func mixedMsgKindNames() []string {
+	names := make([]string, 0, len(tx.MixedMsgKinds))
+	for _, kind := range tx.MixedMsgKinds {
+		names = append(names, string(kind))
+	}
+
+	return names
+}
+
+// defaultMixedWeights returns the weights of every kind of liquidity message of 1.
+func defaultMixedWeights() string {
+	weights := make([]string, 0, len(tx.MixedMsgKinds))
+	for _, name := range mixedMsgKindNames() {
+		weights = append(weights, name+"=1")
+	}
+
+	return strings.Join(weights, ",")
+}
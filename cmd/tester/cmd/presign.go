@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// PresignCmd signs swap transactions of the worker accounts ahead of time and writes them to a presigned tx file.
+// This command is useful to benchmark broadcasting with the blast command without the signing time.
+func PresignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "presign [pool-id] [offer-coin] [demand-coin-denom] [tx-num] [msg-num]",
+		Short:   "sign swap transactions to a presigned tx file to be broadcast by blast.",
+		Aliases: []string{"ps"},
+		Args:    cobra.ExactArgs(5),
+		Long: `Sign tx-num swap transactions spread over the worker accounts and write them to a presigned tx file.
+Every line of the file is a JSON object of a transaction in base64 with its account, sequence and message types.
+The transactions are signed with the sequences following the committed sequences of the accounts,
+so the accounts must not send other transactions until the file is broadcast with the blast command.
+
+Example: $ tester presign 1 5000000ubtsg uatom 1000 2 --output presigned.jsonl
+
+tx-num: how many transactions to sign
+msg-num: how many transaction messages to be included in a transaction
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+			if err != nil {
+				return err
+			}
+			defer client.Stop() // nolint: errcheck
+
+			poolId, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("pool-id %s not a valid uint, input a valid unsigned 32-bit integer for pool-id", args[0])
+			}
+
+			offerCoin, err := sdktypes.ParseCoinNormalized(args[1])
+			if err != nil {
+				return err
+			}
+
+			err = offerCoin.Validate()
+			if err != nil {
+				return err
+			}
+
+			err = sdktypes.ValidateDenom(args[2])
+			if err != nil {
+				return err
+			}
+
+			txNum, err := strconv.Atoi(args[3])
+			if err != nil || txNum <= 0 {
+				return fmt.Errorf("tx-num must be positive integer: %s", args[3])
+			}
+
+			msgNum, err := strconv.Atoi(args[4])
+			if err != nil {
+				return fmt.Errorf("msg-num must be integer: %s", args[4])
+			}
+
+			output, err := cmd.Flags().GetString(flagOutput)
+			if err != nil {
+				return err
+			}
+
+			chainID, err := client.RPC.GetNetworkChainID(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, accounts, err := recoverAccounts(cfg)
+			if err != nil {
+				return err
+			}
+
+			workers := newWorkers(accounts, txNum)
+			seqs := tx.NewSequenceManager(client)
+
+			t, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
+
+			_, err = applyTargetSize(ctx, client, cfg, t, txNum)
+			if err != nil {
+				return err
+			}
+
+			msgTypes := make(map[string][]string)
+			for _, w := range workers {
+				w.msgs, err = t.CreateSwapBot(ctx, w.Address, poolId, offerCoin, args[2], msgNum)
+				if err != nil {
+					return fmt.Errorf("failed to create msg: %s", err)
+				}
+
+				msgTypes[w.Address], err = tx.MsgTypeURLs(w.msgs)
+				if err != nil {
+					return err
+				}
+			}
+
+			txs, err := signRound(ctx, t, seqs, workers, txNum)
+			if err != nil {
+				return err
+			}
+
+			presigned := make([]tx.PresignedTx, 0, len(txs))
+			for _, stx := range txs {
+				presigned = append(presigned, tx.PresignedTx{
+					Address:       stx.address,
+					AccountNumber: stx.accNum,
+					Sequence:      stx.seq,
+					MsgTypes:      msgTypes[stx.address],
+					TxBytes:       stx.bytes,
+				})
+			}
+
+			err = tx.WritePresignedTxFile(output, presigned)
+			if err != nil {
+				return err
+			}
+
+			log.Info().Msgf("wrote %d transactions of %d accounts to %s", len(presigned), len(workers), output)
+			logSignModes(t)
+			logGasEstimates(t)
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagOutput, "presigned.jsonl", "Presigned tx file to write the transactions to.")
+	return cmd
+}
@@ -0,0 +1,371 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/client/grpc"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagGRPCEndpoints = "grpc-endpoints"
+
+	// stages of the duplicates counted by the replay command
+	stageOriginal        = "original"
+	stageSameNode        = "same-node"
+	stageOtherNode       = "other-node"
+	stageCommittedReplay = "committed-replay"
+)
+
+// replayedTx is a transaction whose duplicates are broadcast by the replay command.
+type replayedTx struct {
+	hash  string
+	bytes []byte
+}
+
+// ReplayCmd broadcasts the same signed transactions several times and re-sends them after they are committed.
+// This command is useful to verify that the mempool cache and the ante handler reject replays under load.
+func ReplayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "replay [amount] [tx-num] [copies]",
+		Short:   "broadcast duplicates of transactions and count how nodes reject them.",
+		Aliases: []string{"rp"},
+		Args:    cobra.ExactArgs(3),
+		Long: `Broadcast tx-num transactions sending the amount from every worker account to itself in the sync mode,
+each of them copies times with the same signed bytes. The first copy goes to the configured gRPC endpoint and
+the other copies go to the configured endpoint and the endpoints of the grpc-endpoints flag in turn, which
+default to the gRPC addresses of the endpoints of the config. The configured endpoint is excluded from the
+endpoints of other nodes, so that it is not sent more copies than the other nodes.
+The copies are always broadcast in the sync mode over gRPC, so that the rejections of the duplicates are returned;
+the broadcast mode, transport and policy of the config and the flags are not used.
+After the transactions are committed, every committed transaction is broadcast again to every endpoint.
+
+The duplicates are counted by how they were rejected: by the mempool cache, by the sequence check of the ante
+handler or otherwise. The blocks committed during the run are scanned at the end and every transaction that was
+executed more than once is reported as an error.
+
+Example: $ tester replay 1stake 100 3 --grpc-endpoints 192.168.0.2:9090,192.168.0.3:9090
+
+[amount]: coins every transaction sends
+[tx-num]: how many transactions to broadcast
+[copies]: how many times every transaction is broadcast before it is committed
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			amount, err := sdktypes.ParseCoinsNormalized(args[0])
+			if err != nil {
+				return err
+			}
+
+			txNum, err := strconv.Atoi(args[1])
+			if err != nil || txNum <= 0 {
+				return fmt.Errorf("tx-num must be positive integer: %s", args[1])
+			}
+
+			copies, err := strconv.Atoi(args[2])
+			if err != nil || copies <= 0 {
+				return fmt.Errorf("copies must be positive integer: %s", args[2])
+			}
+
+			endpointsStr, err := cmd.Flags().GetString(flagGRPCEndpoints)
+			if err != nil {
+				return err
+			}
+
+			waitBlocks, err := cmd.Flags().GetInt64(flagWaitBlocks)
+			if err != nil {
+				return err
+			}
+
+			if waitBlocks <= 0 {
+				return fmt.Errorf("wait-blocks must be positive integer: %d", waitBlocks)
+			}
+
+			// the gRPC addresses of the configured endpoints are used unless the flag is set
+			addresses := strings.Split(endpointsStr, ",")
+			if endpointsStr == "" {
+				addresses = nil
+				for _, e := range cfg.Endpoints {
+					addresses = append(addresses, e.GRPC)
+				}
+			}
+
+			// the endpoints of other nodes come after the configured endpoint, which is excluded from them
+			// so that the configured node is not sent more copies than the other nodes
+			var others []*grpc.Client
+			for _, address := range addresses {
+				address = strings.TrimSpace(address)
+				if address == "" {
+					continue
+				}
+
+				if address == cfg.GRPC.Address {
+					log.Debug().Msgf("skipping the configured gRPC endpoint among the other nodes; address:%s", address)
+					continue
+				}
+
+				endpoint, err := grpc.NewClient(address, client.DefaultGRPCTimeout, client.DefaultCommitTimeout)
+				if err != nil {
+					return err
+				}
+				defer endpoint.Close() // nolint: errcheck
+
+				others = append(others, endpoint)
+			}
+
+			client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+			if err != nil {
+				return err
+			}
+			defer client.Stop() // nolint: errcheck
+
+			endpoints := append([]*grpc.Client{client.GRPC}, others...)
+
+			chainID, err := client.RPC.GetNetworkChainID(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, accounts, err := recoverAccounts(cfg)
+			if err != nil {
+				return err
+			}
+
+			workers := newWorkers(accounts, txNum)
+			for _, w := range workers {
+				msg, err := tx.MsgSend(w.Address, w.Address, amount)
+				if err != nil {
+					return fmt.Errorf("failed to create msg: %s", err)
+				}
+				w.msgs = []sdktypes.Msg{msg}
+			}
+
+			seqs := tx.NewSequenceManager(client)
+
+			t, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
+
+			startHeight, err := client.RPC.GetLatestBlockHeight(ctx)
+			if err != nil {
+				return err
+			}
+
+			stats := newCodeStats()
+
+			// the transactions of a worker are broadcast one by one, so that the original of every transaction
+			// passes CheckTx before the next one is signed
+			var mu sync.Mutex
+			var sent []replayedTx
+
+			var wg sync.WaitGroup
+			errs := make([]error, len(workers))
+			for i, w := range workers {
+				num := txNum / len(workers)
+				if i < txNum%len(workers) {
+					num++
+				}
+
+				wg.Add(1)
+				go func(i int, w *worker, num int) {
+					defer wg.Done()
+
+					for j := 0; j < num; j++ {
+						rtx, ok, err := broadcastDuplicates(ctx, t, seqs, endpoints, w, copies, stats)
+						if err != nil {
+							errs[i] = err
+							return
+						}
+
+						if ok {
+							mu.Lock()
+							sent = append(sent, rtx)
+							mu.Unlock()
+						}
+					}
+				}(i, w, num)
+			}
+			wg.Wait()
+
+			for _, err := range errs {
+				if err != nil {
+					return err
+				}
+			}
+
+			err = waitForBlocks(ctx, client, waitBlocks)
+			if err != nil {
+				return err
+			}
+
+			executions := make(map[string]int)
+			for _, rtx := range sent {
+				executions[rtx.hash] = 0
+			}
+
+			height, err := countExecutions(ctx, client, executions, startHeight+1)
+			if err != nil {
+				return err
+			}
+
+			var committed int
+			for _, rtx := range sent {
+				if executions[rtx.hash] == 0 {
+					continue
+				}
+				committed++
+
+				for _, endpoint := range endpoints {
+					stats.add(stageCommittedReplay, broadcastDuplicate(ctx, endpoint, rtx.bytes))
+				}
+			}
+
+			err = waitForBlocks(ctx, client, waitBlocks)
+			if err != nil {
+				return err
+			}
+
+			_, err = countExecutions(ctx, client, executions, height+1)
+			if err != nil {
+				return err
+			}
+
+			stats.log([]string{stageOriginal, stageSameNode, stageOtherNode, stageCommittedReplay})
+
+			log.Info().Msgf("txs:%d; committed:%d; endpoints:%d", len(sent), committed, len(endpoints))
+
+			var executedTwice int
+			for _, rtx := range sent {
+				if executions[rtx.hash] > 1 {
+					log.Error().Msgf("tx %s executed %d times", rtx.hash, executions[rtx.hash])
+					executedTwice++
+				}
+			}
+
+			if executedTwice > 0 {
+				return fmt.Errorf("%d duplicated txs were executed more than once", executedTwice)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagGRPCEndpoints, "", "Comma separated gRPC endpoints of other nodes to broadcast duplicates to. Defaults to the endpoints of the config.")
+	cmd.Flags().Int64(flagWaitBlocks, 2, "Number of blocks to wait for the transactions to be committed.")
+	return cmd
+}
+
+// broadcastDuplicates signs a transaction with the next sequence of the worker, broadcasts it to the first endpoint
+// and then broadcasts copies-1 duplicates of it to the endpoints in turn. It returns false when the original is
+// rejected, in which case no duplicates are broadcast.
+func broadcastDuplicates(ctx context.Context, t *tx.Transaction, seqs *tx.SequenceManager, endpoints []*grpc.Client,
+	w *worker, copies int, stats *codeStats) (replayedTx, bool, error) {
+	accNum, accSeq, err := seqs.Next(ctx, w.Address)
+	if err != nil {
+		return replayedTx{}, false, err
+	}
+
+	txByte, err := t.Sign(ctx, accSeq, accNum, w.Signer, w.msgs...)
+	if err != nil {
+		return replayedTx{}, false, fmt.Errorf("failed to sign transaction: %s", err)
+	}
+
+	resp, err := endpoints[0].BroadcastTxMode(ctx, txByte, sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+	if err != nil {
+		seqs.Release(w.Address, accSeq)
+		log.Debug().Msgf("failed to broadcast tx of %s: %s", w.Address, err)
+		stats.add(stageOriginal, "broadcast-error")
+		return replayedTx{}, false, nil
+	}
+
+	seqs.Report(w.Address, accSeq, resp.TxResponse)
+
+	if resp.TxResponse.Code != 0 {
+		code := fmt.Sprintf("%s/%d", resp.TxResponse.Codespace, resp.TxResponse.Code)
+		log.Debug().Msgf("tx of %s rejected: code:%s; log:%s", w.Address, code, resp.TxResponse.RawLog)
+		stats.add(stageOriginal, code)
+		return replayedTx{}, false, nil
+	}
+	stats.add(stageOriginal, "ok")
+
+	for i := 1; i < copies; i++ {
+		endpoint := i % len(endpoints)
+
+		stage := stageSameNode
+		if endpoint != 0 {
+			stage = stageOtherNode
+		}
+
+		stats.add(stage, broadcastDuplicate(ctx, endpoints[endpoint], txByte))
+	}
+
+	return replayedTx{hash: tx.TxHash(txByte), bytes: txByte}, true, nil
+}
+
+// broadcastDuplicate broadcasts the duplicate in the sync mode and returns how it was rejected.
+func broadcastDuplicate(ctx context.Context, endpoint *grpc.Client, txByte []byte) string {
+	resp, err := endpoint.BroadcastTxMode(ctx, txByte, sdktx.BroadcastMode_BROADCAST_MODE_SYNC)
+	if err != nil {
+		log.Debug().Msgf("failed to broadcast duplicate: %s", err)
+		return "broadcast-error"
+	}
+
+	rejection := tx.ClassifyRejection(resp.TxResponse)
+	if rejection == tx.RejectionOther {
+		log.Debug().Msgf("duplicate rejected: code:%s/%d; log:%s", resp.TxResponse.Codespace, resp.TxResponse.Code, resp.TxResponse.RawLog)
+	}
+
+	return string(rejection)
+}
+
+// countExecutions adds the number of successful executions of the transactions of the hashes in the blocks from the
+// height to the latest block, and returns the latest block height.
+func countExecutions(ctx context.Context, c *client.Client, executions map[string]int, from int64) (int64, error) {
+	to, err := c.RPC.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for height := from; height <= to; height++ {
+		txs, results, err := c.RPC.GetBlockTxResults(ctx, height)
+		if err != nil {
+			return 0, err
+		}
+
+		for i, txByte := range txs {
+			hash := tx.TxHash(txByte)
+			if _, ok := executions[hash]; !ok {
+				continue
+			}
+
+			if i < len(results) && results[i].Code == 0 {
+				executions[hash]++
+			}
+		}
+	}
+
+	return to, nil
+}
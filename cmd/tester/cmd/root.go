@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/b-harvest/cosmos-module-stress-test/config"
+
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -15,8 +17,11 @@ const (
 )
 
 var (
-	logLevel  string
-	logFormat string
+	logLevel           string
+	logFormat          string
+	broadcastMode      string
+	broadcastTransport string
+	broadcastPolicy    string
 )
 
 // RootCmd creates a new root command for tester. It is called once in the main function.
@@ -28,12 +33,25 @@ func RootCmd() *cobra.Command {
 
 	cmd.PersistentFlags().StringVar(&logLevel, "log-level", zerolog.DebugLevel.String(), "logging level;")
 	cmd.PersistentFlags().StringVar(&logFormat, "log-format", logLevelText, "logging format; must be either json or text;")
+	cmd.PersistentFlags().StringVar(&broadcastMode, "broadcast-mode", "", "broadcast mode overriding broadcast_mode of the config; must be either async, sync or block;")
+	cmd.PersistentFlags().StringVar(&broadcastTransport, "broadcast-transport", "", "broadcast transport overriding broadcast_transport of the config; must be either grpc, rpc or lcd;")
+	cmd.PersistentFlags().StringVar(&broadcastPolicy, "broadcast-policy", "", "broadcast policy overriding broadcast_policy of the config; must be either sticky, round-robin, random or all;")
 
 	cmd.AddCommand(CreatePoolsCmd())
 	cmd.AddCommand(DepositCmd())
 	cmd.AddCommand(WithdrawCmd())
 	cmd.AddCommand(SwapCmd())
 	cmd.AddCommand(IBCtransferCmd())
+	cmd.AddCommand(FundAccountsCmd())
+	cmd.AddCommand(SweepCmd())
+	cmd.AddCommand(GenesisAccountsCmd())
+	cmd.AddCommand(GasPriceFloorCmd())
+	cmd.AddCommand(PresignCmd())
+	cmd.AddCommand(BlastCmd())
+	cmd.AddCommand(InvalidCmd())
+	cmd.AddCommand(MixedCmd())
+	cmd.AddCommand(ReplayCmd())
+	cmd.AddCommand(LoadCmd())
 
 	return cmd
 }
@@ -57,3 +75,18 @@ func SetLogger(logLevel string) error {
 
 	return nil
 }
+
+// ReadConfig reads the config file and applies the chain config to the global Cosmos SDK config.
+func ReadConfig() (*config.Config, error) {
+	cfg, err := config.Read(config.DefaultConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.Chain.Apply()
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply chain config: %s", err)
+	}
+
+	return cfg, nil
+}
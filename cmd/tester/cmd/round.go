@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/rs/zerolog/log"
+)
+
+// signedTx is a signed transaction with the account and the sequence it is signed with.
+type signedTx struct {
+	address string
+	accNum  uint64
+	seq     uint64
+	bytes   []byte
+}
+
+// signRound signs txNum transactions of the workers' messages, spreading them over the workers.
+// The sequences are handed out by the sequence manager after refreshing the workers' committed sequences,
+// and the transactions are signed concurrently and returned in the order the sequences are handed out.
+func signRound(ctx context.Context, t *tx.Transaction, seqs *tx.SequenceManager, workers []*worker, txNum int) ([]signedTx, error) {
+	for _, w := range workers {
+		err := seqs.Refresh(ctx, w.Address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	txs := make([]signedTx, 0, txNum)
+	jobs := make([]tx.SignJob, 0, txNum)
+	for i := 0; i < txNum; i++ {
+		w := workers[i%len(workers)]
+
+		accNum, accSeq, err := seqs.Next(ctx, w.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, signedTx{
+			address: w.Address,
+			accNum:  accNum,
+			seq:     accSeq,
+		})
+		jobs = append(jobs, tx.SignJob{
+			AccNum: accNum,
+			Seq:    accSeq,
+			Signer: w.Signer,
+			Msgs:   w.msgs,
+		})
+	}
+
+	txBytes, err := t.SignAll(ctx, jobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign and broadcast: %s", err)
+	}
+
+	for i := range txs {
+		txs[i].bytes = txBytes[i]
+	}
+
+	return txs, nil
+}
+
+// broadcastRound broadcasts the signed transactions and reports their responses to the sequence manager.
+// The async mode returns before CheckTx and reports no rejected transaction, so the sequences are only resynced
+// by Refresh in that mode.
+// When the broadcast of a transaction failed for good, the later transactions of its account in the round are skipped,
+// since they would be rejected with a wrong sequence, and the sequence of the failed transaction is handed out again
+// once the round is broadcast. The round stops only when the context is done.
+func broadcastRound(ctx context.Context, b *txBroadcaster, seqs *tx.SequenceManager, txs []signedTx) error {
+	failed := make(map[string]uint64)
+	for _, stx := range txs {
+		if _, ok := failed[stx.address]; ok {
+			continue
+		}
+
+		resp, err := b.broadcast(ctx, stx.address, stx.bytes)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+
+			log.Warn().Msgf("skipped txs of %s from sequence %d: %s", stx.address, stx.seq, err)
+			failed[stx.address] = stx.seq
+			continue
+		}
+
+		if seqs.Report(stx.address, stx.seq, resp) {
+			log.Warn().Msgf("resynced sequence of %s after rejected tx: code:%d; log:%s", stx.address, resp.Code, resp.RawLog)
+		}
+	}
+
+	for address, seq := range failed {
+		seqs.Release(address, seq)
+	}
+
+	return nil
+}
+
+// logSequenceStats logs the broadcast results of every worker at the end of a run, labeled by what the broadcast
+// mode reports: the CheckTx result in the sync mode and the DeliverTx result in the block mode. The async mode
+// reports no result, so only the number of transactions broadcast and resyncs are logged.
+func logSequenceStats(seqs *tx.SequenceManager, workers []*worker, mode sdktx.BroadcastMode) {
+	for _, w := range workers {
+		stats := seqs.Stats(w.Address)
+		switch mode {
+		case sdktx.BroadcastMode_BROADCAST_MODE_ASYNC:
+			log.Info().Msgf("accAddr:%s; broadcast:%d; resyncs:%d", w.Address, stats.Accepted+stats.Rejected, stats.Resyncs)
+		case sdktx.BroadcastMode_BROADCAST_MODE_BLOCK:
+			log.Info().Msgf("accAddr:%s; committed:%d; deliverFailed:%d; rejected:%d; inCache:%d; resyncs:%d",
+				w.Address, stats.Accepted, stats.DeliverFailed, stats.Rejected, stats.InCache, stats.Resyncs)
+		default:
+			log.Info().Msgf("accAddr:%s; checkTxAccepted:%d; checkTxRejected:%d; inCache:%d; resyncs:%d",
+				w.Address, stats.Accepted, stats.Rejected, stats.InCache, stats.Resyncs)
+		}
+	}
+}
+
+// logSignModes logs the sign mode of a run and how many transactions were signed in each sign mode.
+func logSignModes(t *tx.Transaction) {
+	counts := t.SignModeCounts()
+
+	modes := make([]string, 0, len(counts))
+	for mode, count := range counts {
+		modes = append(modes, fmt.Sprintf("%s:%d", mode, count))
+	}
+	sort.Strings(modes)
+
+	log.Info().Msgf("signMode:%s; %s", t.SignMode, strings.Join(modes, "; "))
+}
+
+// logGasEstimates logs the gas limit estimated for every message shape when gas is estimated.
+func logGasEstimates(t *tx.Transaction) {
+	if t.GasEstimator == nil {
+		return
+	}
+
+	for shape, gasLimit := range t.GasEstimator.Estimates() {
+		log.Info().Msgf("msgs:%s; estimatedGas:%d", shape, gasLimit)
+	}
+}
+
+// sizeReport records the sizes of the transactions of a run and reports them with the fill ratios of the blocks
+// committed during the run when the transactions are padded.
+type sizeReport struct {
+	maxBytes    int64
+	startHeight int64
+
+	count int
+	total int
+	min   int
+	max   int
+}
+
+// newSizeReport returns a size report of the run starting at the latest block.
+// The blocks are not reported when maxBytes is 0.
+func newSizeReport(ctx context.Context, c *client.Client, maxBytes int64) (*sizeReport, error) {
+	startHeight, err := c.RPC.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sizeReport{
+		maxBytes:    maxBytes,
+		startHeight: startHeight,
+	}, nil
+}
+
+// add records the sizes of the signed transactions.
+func (r *sizeReport) add(txs []signedTx) {
+	for _, stx := range txs {
+		size := len(stx.bytes)
+		if r.count == 0 || size < r.min {
+			r.min = size
+		}
+		if size > r.max {
+			r.max = size
+		}
+		r.count++
+		r.total += size
+	}
+}
+
+// log logs the sizes of the transactions and the fill ratio of every block committed since the run started.
+// It waits for a block first, so that the transactions of the last round are committed.
+func (r *sizeReport) log(ctx context.Context, c *client.Client) error {
+	if r.count == 0 {
+		return nil
+	}
+
+	log.Info().Msgf("txs:%d; minTxSize:%d; avgTxSize:%d; maxTxSize:%d", r.count, r.min, r.total/r.count, r.max)
+
+	if r.maxBytes == 0 {
+		return nil
+	}
+
+	err := waitForBlocks(ctx, c, 1)
+	if err != nil {
+		return err
+	}
+
+	endHeight, err := c.RPC.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	var totalSize int
+	for height := r.startHeight + 1; height <= endHeight; height++ {
+		size, numTxs, err := c.RPC.GetBlockSize(ctx, height)
+		if err != nil {
+			return err
+		}
+		totalSize += size
+
+		log.Info().Msgf("height:%d; txs:%d; blockSize:%d; fill:%.2f%%", height, numTxs, size, 100*float64(size)/float64(r.maxBytes))
+	}
+
+	if blocks := endHeight - r.startHeight; blocks > 0 {
+		log.Info().Msgf("blocks:%d; avgFill:%.2f%%", blocks, 100*float64(totalSize)/float64(blocks)/float64(r.maxBytes))
+	}
+
+	return nil
+}
@@ -6,9 +6,7 @@ import (
 	"strconv"
 
 	"github.com/b-harvest/cosmos-module-stress-test/client"
-	"github.com/b-harvest/cosmos-module-stress-test/config"
 	"github.com/b-harvest/cosmos-module-stress-test/tx"
-	"github.com/b-harvest/cosmos-module-stress-test/wallet"
 
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 
@@ -27,7 +25,7 @@ func SwapCmd() *cobra.Command {
 Example: $ tester s 1 5000000ubtsg uatom 5 5 2
 
 round: how many rounds to run
-tx-num: how many transactions to be included in a block
+tx-num: how many transactions to be included in a round, which is broadcast right after a block with --block-sync
 msg-num: how many transaction messages to be included in a transaction
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -39,7 +37,7 @@ msg-num: how many transaction messages to be included in a transaction
 				return err
 			}
 
-			cfg, err := config.Read(config.DefaultConfigPath)
+			cfg, err := ReadConfig()
 			if err != nil {
 				return err
 			}
@@ -76,8 +74,8 @@ msg-num: how many transaction messages to be included in a transaction
 			}
 
 			txNum, err := strconv.Atoi(args[4])
-			if err != nil {
-				return fmt.Errorf("tx-num must be integer: %s", args[4])
+			if err != nil || txNum <= 0 {
+				return fmt.Errorf("tx-num must be positive integer: %s", args[4])
 			}
 
 			msgNum, err := strconv.Atoi(args[5])
@@ -90,58 +88,81 @@ msg-num: how many transaction messages to be included in a transaction
 				return err
 			}
 
-			accAddr, privKey, err := wallet.RecoverAccountFromMnemonic(cfg.Custom.Mnemonic, "")
+			_, accounts, err := recoverAccounts(cfg)
 			if err != nil {
 				return err
 			}
 
-			gasLimit := uint64(cfg.Custom.GasLimit)
-			fees := sdktypes.NewCoins(sdktypes.NewCoin(cfg.Custom.FeeDenom, sdktypes.NewInt(cfg.Custom.FeeAmount)))
-			memo := cfg.Custom.Memo
+			workers := newWorkers(accounts, txNum)
+			seqs := tx.NewSequenceManager(client)
 
-			tx := tx.NewTransaction(client, chainID, gasLimit, fees, memo)
+			broadcaster, err := newTxBroadcaster(client, cfg)
+			if err != nil {
+				return err
+			}
+			defer broadcaster.close() // nolint: errcheck
 
-			for i := 0; i < round; i++ {
-				var txBytes [][]byte
+			tx, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
 
-				account, err := client.GRPC.GetBaseAccountInfo(ctx, accAddr)
-				if err != nil {
-					return fmt.Errorf("failed to get account information: %s", err)
-				}
+			maxBytes, err := applyTargetSize(ctx, client, cfg, tx, txNum)
+			if err != nil {
+				return err
+			}
 
-				accSeq := account.GetSequence()
-				accNum := account.GetAccountNumber()
+			report, err := newSizeReport(ctx, client, maxBytes)
+			if err != nil {
+				return err
+			}
 
-				msgs, err := tx.CreateSwapBot(ctx, accAddr, poolId, offerCoin, args[2], msgNum)
-				if err != nil {
-					return fmt.Errorf("failed to create msg: %s", err)
-				}
+			blocks, err := newBlockSync(ctx, cmd, client)
+			if err != nil {
+				return err
+			}
 
-				for i := 0; i < txNum; i++ {
-					txByte, err := tx.Sign(ctx, accSeq, accNum, privKey, msgs...)
+			for i := 0; i < round; i++ {
+				for _, w := range workers {
+					w.msgs, err = tx.CreateSwapBot(ctx, w.Address, poolId, offerCoin, args[2], msgNum)
 					if err != nil {
-						return fmt.Errorf("failed to sign and broadcast: %s", err)
+						return fmt.Errorf("failed to create msg: %s", err)
 					}
+				}
 
-					accSeq = accSeq + 1
-
-					txBytes = append(txBytes, txByte)
+				txs, err := signRound(ctx, tx, seqs, workers, txNum)
+				if err != nil {
+					return err
 				}
+				report.add(txs)
 
-				log.Info().Msgf("round:%d; txNum:%d; msgNum: %d; accAddr:%s", i+1, txNum, msgNum, accAddr)
+				log.Info().Msgf("round:%d; txNum:%d; msgNum: %d; accounts:%d", i+1, txNum, msgNum, len(workers))
 
-				for _, txByte := range txBytes {
-					resp, err := client.GRPC.BroadcastTx(ctx, txByte)
-					if err != nil {
-						return fmt.Errorf("failed to broadcast transaction: %s", err)
-					}
+				err = blocks.wait(ctx)
+				if err != nil {
+					return err
+				}
 
-					log.Info().Msgf("%s/cosmos/tx/v1beta1/txs/%s", cfg.LCD.Address, resp.TxResponse.TxHash)
+				err = broadcastRound(ctx, broadcaster, seqs, txs)
+				if err != nil {
+					return err
 				}
 			}
 
+			blocks.log()
+			logSequenceStats(seqs, workers, broadcaster.mode)
+			broadcaster.log(ctx)
+			logSignModes(tx)
+			logGasEstimates(tx)
+
+			err = report.log(ctx, client)
+			if err != nil {
+				return err
+			}
+
 			return nil
 		},
 	}
+	cmd.Flags().Bool(flagBlockSync, false, "Broadcast every round right after a new block is committed and wait for the following block before the next round.")
 	return cmd
 }
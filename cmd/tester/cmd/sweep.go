@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFeeReserve = "fee-reserve"
+	flagWaitBlocks = "wait-blocks"
+)
+
+// SweepCmd reclaims the coins left in the worker accounts to the master account.
+// This command is useful to recycle testnet funds between stress testing runs.
+func SweepCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "sweep",
+		Short:   "withdraw pool coins and send every balance of the worker accounts back to the master account.",
+		Aliases: []string{"sw"},
+		Args:    cobra.NoArgs,
+		Long: `Withdraw pool coins and send every balance of the worker accounts back to the master account.
+Pool coins held by the worker accounts are withdrawn from their pools first and the withdrawn coins are
+swept after wait-blocks blocks. The fee reserve, which defaults to the configured fee, is left in every account.
+
+Example: $ tester sweep --fee-reserve 10000stake --wait-blocks 2
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := SetLogger(logLevel)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := ReadConfig()
+			if err != nil {
+				return err
+			}
+
+			client, err := client.NewClient(cfg.RPC.Address, cfg.GRPC.Address)
+			if err != nil {
+				return err
+			}
+			defer client.Stop() // nolint: errcheck
+
+			feeReserveStr, err := cmd.Flags().GetString(flagFeeReserve)
+			if err != nil {
+				return err
+			}
+
+			waitBlocks, err := cmd.Flags().GetInt64(flagWaitBlocks)
+			if err != nil {
+				return err
+			}
+
+			chainID, err := client.RPC.GetNetworkChainID(ctx)
+			if err != nil {
+				return err
+			}
+
+			master, accounts, err := recoverAccounts(cfg)
+			if err != nil {
+				return err
+			}
+
+			pools, err := client.GRPC.GetAllPools(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get pools: %s", err)
+			}
+
+			poolIds := make(map[string]uint64)
+			for _, pool := range pools {
+				poolIds[pool.PoolCoinDenom] = pool.Id
+			}
+
+			broadcaster, err := newTxBroadcaster(client, cfg)
+			if err != nil {
+				return err
+			}
+			defer broadcaster.close() // nolint: errcheck
+
+			tx, err := newTransaction(client, chainID, cfg)
+			if err != nil {
+				return err
+			}
+
+			feeReserve := tx.Fee(tx.GasLimit)
+			if feeReserveStr != "" {
+				feeReserve, err = sdktypes.ParseCoinsNormalized(feeReserveStr)
+				if err != nil {
+					return err
+				}
+			}
+
+			// withdraw pool coins first, so that the reserve coins are swept as well
+			withdrawn := false
+			for _, acc := range accounts {
+				if acc.Address == master.Address {
+					continue
+				}
+
+				balances, err := client.GRPC.GetAllBalances(ctx, acc.Address)
+				if err != nil {
+					return fmt.Errorf("failed to get balances of %s: %s", acc.Address, err)
+				}
+
+				msgs, err := withdrawPoolCoinMsgs(acc.Address, balances, poolIds)
+				if err != nil {
+					return fmt.Errorf("failed to create msg: %s", err)
+				}
+
+				if len(msgs) == 0 {
+					continue
+				}
+
+				log.Info().Msgf("withdrawing %d pool coins of %s", len(msgs), acc.Address)
+
+				err = signAndBroadcast(ctx, broadcaster, tx, acc, msgs...)
+				if err != nil {
+					return err
+				}
+				withdrawn = true
+			}
+
+			if withdrawn {
+				err = waitForBlocks(ctx, client, waitBlocks)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, acc := range accounts {
+				if acc.Address == master.Address {
+					continue
+				}
+
+				balances, err := client.GRPC.GetAllBalances(ctx, acc.Address)
+				if err != nil {
+					return fmt.Errorf("failed to get balances of %s: %s", acc.Address, err)
+				}
+
+				msg, err := sweepMsg(acc.Address, master.Address, balances, feeReserve)
+				if err != nil {
+					return fmt.Errorf("failed to create msg: %s", err)
+				}
+
+				if msg == nil {
+					log.Debug().Msgf("skipping %s which holds nothing but the fee reserve", acc.Address)
+					continue
+				}
+
+				log.Info().Msgf("sweeping %s", acc.Address)
+
+				err = signAndBroadcast(ctx, broadcaster, tx, acc, msg)
+				if err != nil {
+					return err
+				}
+			}
+
+			broadcaster.log(ctx)
+
+			return nil
+		},
+	}
+	cmd.Flags().String(flagFeeReserve, "", "Coins left in every account to pay fees. Defaults to the fee of the configured gas limit.")
+	cmd.Flags().Int64(flagWaitBlocks, 2, "Number of blocks to wait for the pool coin withdrawals to be executed.")
+	return cmd
+}
+
+// withdrawPoolCoinMsgs returns withdraw messages for every pool coin found in the balances.
+func withdrawPoolCoinMsgs(address string, balances sdktypes.Coins, poolIds map[string]uint64) ([]sdktypes.Msg, error) {
+	var msgs []sdktypes.Msg
+	for _, coin := range balances {
+		poolId, ok := poolIds[coin.Denom]
+		if !ok {
+			continue
+		}
+
+		msg, err := tx.MsgWithdraw(address, poolId, coin)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// sweepMsg returns a send message of the balances except the fee reserve.
+// It returns nil when nothing is left to send.
+func sweepMsg(fromAddr string, toAddr string, balances sdktypes.Coins, feeReserve sdktypes.Coins) (sdktypes.Msg, error) {
+	amount := sdktypes.NewCoins()
+	for _, coin := range balances {
+		remainder := coin.Amount.Sub(feeReserve.AmountOf(coin.Denom))
+		if remainder.IsPositive() {
+			amount = amount.Add(sdktypes.NewCoin(coin.Denom, remainder))
+		}
+	}
+
+	if amount.Empty() {
+		return nil, nil
+	}
+
+	return tx.MsgSend(fromAddr, toAddr, amount)
+}
+
+// signAndBroadcast signs the messages with the current sequence of the account and broadcasts the transaction.
+func signAndBroadcast(ctx context.Context, b *txBroadcaster, t *tx.Transaction, acc wallet.Account, msgs ...sdktypes.Msg) error {
+	account, err := b.client.GRPC.GetBaseAccountInfo(ctx, acc.Address)
+	if err != nil {
+		return fmt.Errorf("failed to get account information: %s", err)
+	}
+
+	txByte, err := t.Sign(ctx, account.GetSequence(), account.GetAccountNumber(), acc.Signer, msgs...)
+	if err != nil {
+		return fmt.Errorf("failed to sign and broadcast: %s", err)
+	}
+
+	_, err = b.broadcast(ctx, acc.Address, txByte)
+	return err
+}
+
+// waitForBlocks blocks until the given number of blocks are committed on top of the latest block.
+func waitForBlocks(ctx context.Context, c *client.Client, blocks int64) error {
+	startHeight, err := c.RPC.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("waiting for %d blocks from height %d", blocks, startHeight)
+
+	for {
+		height, err := c.RPC.GetLatestBlockHeight(ctx)
+		if err != nil {
+			return err
+		}
+
+		if height >= startHeight+blocks {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
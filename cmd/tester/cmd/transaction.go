@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/config"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/rs/zerolog/log"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// txOverhead is the size of the field tag and the length prefix of a transaction of up to 2 MB in the block data.
+const txOverhead = 4
+
+// newTransaction returns the transaction of the gas limit, the fees, the memo and the sign mode configured in the config.
+// The gas limit of every transaction is estimated by simulating it instead when gas estimation is enabled,
+// and the fees of every transaction are computed from its gas limit when gas prices are set.
+func newTransaction(c *client.Client, chainID string, cfg *config.Config) (*tx.Transaction, error) {
+	signMode, err := tx.ParseSignMode(cfg.Custom.SignMode)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := uint64(cfg.Custom.GasLimit)
+	fees := sdktypes.NewCoins(sdktypes.NewCoin(cfg.Custom.FeeDenom, sdktypes.NewInt(cfg.Custom.FeeAmount)))
+
+	t := tx.NewTransaction(c, chainID, gasLimit, fees, cfg.Custom.Memo)
+	t.SignMode = signMode
+	t.SignWorkers = cfg.Custom.SignWorkers
+
+	if cfg.Custom.GasPrices != "" {
+		if !fees.IsZero() {
+			return nil, fmt.Errorf("cannot set both fee_amount and gas_prices")
+		}
+
+		gasPrices, err := sdktypes.ParseDecCoins(cfg.Custom.GasPrices)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse gas_prices: %s", err)
+		}
+		t.GasPrices = gasPrices
+	}
+
+	if cfg.Custom.EstimateGas {
+		adjustment := cfg.Custom.GasAdjustment
+		if adjustment == 0 {
+			adjustment = 1
+		}
+		if adjustment < 1 {
+			return nil, fmt.Errorf("gas_adjustment must not be less than 1: %f", adjustment)
+		}
+
+		t.GasEstimator = tx.NewGasEstimator(adjustment)
+	}
+
+	return t, nil
+}
+
+// applyTargetSize sets the target size of the transactions configured in the config and returns the max block bytes
+// of the consensus params, or 0 when the transactions are not padded.
+// When the blocks are to be filled, the target size is the max data bytes of a block split over txNum transactions.
+func applyTargetSize(ctx context.Context, c *client.Client, cfg *config.Config, t *tx.Transaction, txNum int) (int64, error) {
+	if cfg.Custom.TargetTxSize == 0 && !cfg.Custom.FillBlocks {
+		return 0, nil
+	}
+
+	if cfg.Custom.TargetTxSize != 0 && cfg.Custom.FillBlocks {
+		return 0, fmt.Errorf("cannot set both target_tx_size and fill_blocks")
+	}
+
+	if cfg.Custom.FillBlocks && txNum <= 0 {
+		return 0, fmt.Errorf("tx-num must be positive to fill blocks: %d", txNum)
+	}
+
+	params, err := c.RPC.GetConsensusParams(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	valsCount, err := c.RPC.GetValidatorCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	authParams, err := c.GRPC.GetAuthParams(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get auth params: %s", err)
+	}
+
+	maxDataBytes := tmtypes.MaxDataBytesNoEvidence(params.Block.MaxBytes, valsCount)
+
+	targetSize := int64(cfg.Custom.TargetTxSize)
+	if cfg.Custom.FillBlocks {
+		// every transaction in the block data is prefixed with its field tag and length
+		targetSize = maxDataBytes/int64(txNum) - txOverhead
+	}
+
+	if targetSize <= 0 || targetSize > maxDataBytes {
+		return 0, fmt.Errorf("target tx size %d must be between 1 and the max data bytes of a block %d", targetSize, maxDataBytes)
+	}
+
+	t.TargetSize = int(targetSize)
+	t.MaxMemoCharacters = authParams.MaxMemoCharacters
+	t.TxSizeCostPerByte = authParams.TxSizeCostPerByte
+
+	log.Info().Msgf("padding txs to %d bytes; maxBlockBytes:%d; maxDataBytes:%d; maxMemoCharacters:%d", targetSize, params.Block.MaxBytes, maxDataBytes, authParams.MaxMemoCharacters)
+
+	return params.Block.MaxBytes, nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// parseWeights parses comma separated kind=weight pairs of the allowed kinds.
+// Kinds of zero weight are left out, and a kind may be given only once.
+func parseWeights(s string, allowed []string) ([]string, []int, error) {
+	var kinds []string
+	var weights []int
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 {
+			return nil, nil, fmt.Errorf("weight must be kind=weight: %s", pair)
+		}
+
+		if !containsString(allowed, kv[0]) {
+			return nil, nil, fmt.Errorf("unknown kind %s; must be one of %s", kv[0], strings.Join(allowed, ", "))
+		}
+
+		if seen[kv[0]] {
+			return nil, nil, fmt.Errorf("duplicate weight of %s", kv[0])
+		}
+		seen[kv[0]] = true
+
+		weight, err := strconv.Atoi(kv[1])
+		if err != nil || weight < 0 {
+			return nil, nil, fmt.Errorf("weight of %s must be non-negative integer: %s", kv[0], kv[1])
+		}
+
+		if weight == 0 {
+			continue
+		}
+
+		kinds = append(kinds, kv[0])
+		weights = append(weights, weight)
+	}
+
+	if len(kinds) == 0 {
+		return nil, nil, fmt.Errorf("at least one kind must have a positive weight")
+	}
+
+	return kinds, weights, nil
+}
+
+// pickKind picks one of the kinds at random by their weights.
+func pickKind(kinds []string, weights []int) string {
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	r := rand.Intn(total)
+	for i, weight := range weights {
+		if r < weight {
+			return kinds[i]
+		}
+		r -= weight
+	}
+
+	return kinds[len(kinds)-1]
+}
+
+// containsString reports whether the string is one of the strings.
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+
+	return false
+}
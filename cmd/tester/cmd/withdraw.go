@@ -6,9 +6,7 @@ import (
 	"strconv"
 
 	"github.com/b-harvest/cosmos-module-stress-test/client"
-	"github.com/b-harvest/cosmos-module-stress-test/config"
 	"github.com/b-harvest/cosmos-module-stress-test/tx"
-	"github.com/b-harvest/cosmos-module-stress-test/wallet"
 
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 
@@ -27,7 +25,7 @@ func WithdrawCmd() *cobra.Command {
 Example: $ tester w 1 10pool94720F40B38D6DD93DCE184D264D4BE089EDF124A9C0658CDBED6CA18CF27752 10 10
 
 [round]: how many rounds to run
-[tx-num]: how many transactions to be included in one round
+[tx-num]: how many transactions to be included in one round, which is broadcast right after a block with --block-sync
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, cancel := context.WithCancel(context.Background())
@@ -38,7 +36,7 @@ Example: $ tester w 1 10pool94720F40B38D6DD93DCE184D264D4BE089EDF124A9C0658CDBED
 				return err
 			}
 
-			cfg, err := config.Read(config.DefaultConfigPath)
+			cfg, err := ReadConfig()
 			if err != nil {
 				return err
 			}
@@ -76,64 +74,87 @@ Example: $ tester w 1 10pool94720F40B38D6DD93DCE184D264D4BE089EDF124A9C0658CDBED
 			}
 
 			txNum, err := strconv.Atoi(args[3])
+			if err != nil || txNum <= 0 {
+				return fmt.Errorf("tx-num must be positive integer: %s", args[3])
+			}
+
+			_, accounts, err := recoverAccounts(cfg)
 			if err != nil {
-				return fmt.Errorf("tx-num must be integer: %s", args[3])
+				return err
+			}
+
+			workers := newWorkers(accounts, txNum)
+			for _, w := range workers {
+				msg, err := tx.MsgWithdraw(w.Address, poolId, poolCoin)
+				if err != nil {
+					return fmt.Errorf("failed to create msg: %s", err)
+				}
+
+				w.msgs = []sdktypes.Msg{msg}
 			}
 
-			accAddr, privKey, err := wallet.RecoverAccountFromMnemonic(cfg.Custom.Mnemonic, "")
+			seqs := tx.NewSequenceManager(client)
+
+			broadcaster, err := newTxBroadcaster(client, cfg)
 			if err != nil {
 				return err
 			}
+			defer broadcaster.close() // nolint: errcheck
 
-			msg, err := tx.MsgWithdraw(accAddr, poolId, poolCoin)
+			tx, err := newTransaction(client, chainID, cfg)
 			if err != nil {
-				return fmt.Errorf("failed to create msg: %s", err)
+				return err
 			}
 
-			msgs := []sdktypes.Msg{msg}
+			maxBytes, err := applyTargetSize(ctx, client, cfg, tx, txNum)
+			if err != nil {
+				return err
+			}
 
-			gasLimit := uint64(cfg.Custom.GasLimit)
-			fees := sdktypes.NewCoins(sdktypes.NewCoin(cfg.Custom.FeeDenom, sdktypes.NewInt(cfg.Custom.FeeAmount)))
-			memo := cfg.Custom.Memo
+			report, err := newSizeReport(ctx, client, maxBytes)
+			if err != nil {
+				return err
+			}
 
-			tx := tx.NewTransaction(client, chainID, gasLimit, fees, memo)
+			blocks, err := newBlockSync(ctx, cmd, client)
+			if err != nil {
+				return err
+			}
 
 			for i := 0; i < round; i++ {
-				var txBytes [][]byte
-
-				account, err := client.GRPC.GetBaseAccountInfo(ctx, accAddr)
+				txs, err := signRound(ctx, tx, seqs, workers, txNum)
 				if err != nil {
-					return fmt.Errorf("failed to get account information: %s", err)
+					return err
 				}
+				report.add(txs)
 
-				accSeq := account.GetSequence()
-				accNum := account.GetAccountNumber()
-
-				for j := 0; j < txNum; j++ {
-					txByte, err := tx.Sign(ctx, accSeq, accNum, privKey, msgs...)
-					if err != nil {
-						return fmt.Errorf("failed to sign and broadcast: %s", err)
-					}
-
-					accSeq = accSeq + 1
+				log.Info().Msgf("round:%d; txNum:%d; accounts:%d", i+1, txNum, len(workers))
 
-					txBytes = append(txBytes, txByte)
+				err = blocks.wait(ctx)
+				if err != nil {
+					return err
 				}
 
-				log.Info().Msgf("round:%d; txNum:%d; accAddr:%s", i+1, txNum, accAddr)
+				err = broadcastRound(ctx, broadcaster, seqs, txs)
+				if err != nil {
+					return err
+				}
+			}
 
-				for _, txByte := range txBytes {
-					resp, err := client.GRPC.BroadcastTx(ctx, txByte)
-					if err != nil {
-						return fmt.Errorf("failed to broadcast transaction: %s", err)
-					}
+			blocks.log()
+			logSequenceStats(seqs, workers, broadcaster.mode)
+			broadcaster.log(ctx)
+			logSignModes(tx)
+			logGasEstimates(tx)
 
-					log.Info().Msgf("%s/cosmos/tx/v1beta1/txs/%s", cfg.LCD.Address, resp.TxResponse.TxHash)
-				}
+			err = report.log(ctx, client)
+			if err != nil {
+				return err
 			}
 
 			return nil
 		},
 	}
+	cmd.Flags().Bool(flagBlockSync, false, "Broadcast every round right after a new block is committed and wait for the following block before the next round.")
 	return cmd
 }
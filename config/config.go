@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/pelletier/go-toml"
 
 	"github.com/rs/zerolog/log"
@@ -15,10 +17,13 @@ var (
 
 // Config defines all necessary configuration parameters.
 type Config struct {
-	RPC    *RPCConfig    `toml:"rpc"`
-	GRPC   *GRPCConfig   `toml:"grpc"`
-	LCD    *LCDConfig    `toml:"lcd"`
-	Custom *CustomConfig `toml:"custom"`
+	RPC       *RPCConfig        `toml:"rpc"`
+	GRPC      *GRPCConfig       `toml:"grpc"`
+	LCD       *LCDConfig        `toml:"lcd"`
+	Chain     *ChainConfig      `toml:"chain"`
+	Keyring   *KeyringConfig    `toml:"keyring"`
+	Custom    *CustomConfig     `toml:"custom"`
+	Endpoints []*EndpointConfig `toml:"endpoints"`
 }
 
 // RPCConfig contains the configuration of the RPC endpoint.
@@ -36,13 +41,57 @@ type LCDConfig struct {
 	Address string `toml:"address"`
 }
 
-//  CustomConfig contains custom configuration for stress testing.
+// EndpointConfig contains the addresses of a node that transactions are broadcast to.
+// Only the address of the broadcast transport is required, and the RPC address is used to query the mempool of the node.
+type EndpointConfig struct {
+	RPC  string `toml:"rpc"`
+	GRPC string `toml:"grpc"`
+	LCD  string `toml:"lcd"`
+}
+
+// ChainConfig contains the address and key derivation parameters of the target chain.
+// Unset fields default to the ones of the Cosmos Hub.
+type ChainConfig struct {
+	AccountPrefix string `toml:"account_prefix"`
+	CoinType      uint32 `toml:"coin_type"`
+	HDPath        string `toml:"hd_path"`
+}
+
+// KeyringConfig contains the configuration of the keyring that stores the keys of the accounts.
+// The keyring is used instead of the mnemonic when a backend is set.
+type KeyringConfig struct {
+	Backend         string `toml:"backend"`
+	Dir             string `toml:"dir"`
+	KeyName         string `toml:"key_name"`
+	WorkerKeyPrefix string `toml:"worker_key_prefix"`
+}
+
+// CustomConfig contains custom configuration for stress testing.
 type CustomConfig struct {
-	Mnemonic  string `toml:"mnemonic"`
-	GasLimit  int64  `toml:"gas_limit"`
-	FeeDenom  string `toml:"fee_denom"`
-	FeeAmount int64  `toml:"fee_amount"`
-	Memo      string `toml:"memo"`
+	Mnemonic           string  `toml:"mnemonic"`
+	AccountIndex       uint32  `toml:"account_index"`
+	AddressIndex       uint32  `toml:"address_index"`
+	NumAccounts        uint32  `toml:"num_accounts"`
+	AccountsFile       string  `toml:"accounts_file"`
+	MultisigThreshold  uint32  `toml:"multisig_threshold"`
+	MultisigKeys       uint32  `toml:"multisig_keys"`
+	GasLimit           int64   `toml:"gas_limit"`
+	EstimateGas        bool    `toml:"estimate_gas"`
+	GasAdjustment      float64 `toml:"gas_adjustment"`
+	FeeDenom           string  `toml:"fee_denom"`
+	FeeAmount          int64   `toml:"fee_amount"`
+	GasPrices          string  `toml:"gas_prices"`
+	Memo               string  `toml:"memo"`
+	SignMode           string  `toml:"sign_mode"`
+	SignWorkers        int     `toml:"sign_workers"`
+	TargetTxSize       int     `toml:"target_tx_size"`
+	FillBlocks         bool    `toml:"fill_blocks"`
+	BroadcastMode      string  `toml:"broadcast_mode"`
+	BroadcastTransport string  `toml:"broadcast_transport"`
+	BroadcastPolicy    string  `toml:"broadcast_policy"`
+	MaxRetries         int     `toml:"max_retries"`
+	RetryBaseDelay     string  `toml:"retry_base_delay"`
+	RetryMaxDelay      string  `toml:"retry_max_delay"`
 }
 
 // NewConfig builds a new Config instance.
@@ -84,3 +133,48 @@ func ParseString(configData []byte) (*Config, error) {
 
 	return &cfg, nil
 }
+
+// Apply sets the bech32 prefixes, coin type and HD path of the chain to the global Cosmos SDK config and seals it.
+// It must be called once at startup, before any address is encoded or any key is derived.
+func (c *ChainConfig) Apply() error {
+	accountPrefix := sdktypes.Bech32MainPrefix
+	coinType := uint32(sdktypes.CoinType)
+	hdPath := ""
+
+	if c != nil {
+		if c.AccountPrefix != "" {
+			accountPrefix = c.AccountPrefix
+		}
+		if c.CoinType != 0 {
+			coinType = c.CoinType
+		}
+		hdPath = c.HDPath
+	}
+
+	if hdPath == "" {
+		hdPath = hd.NewFundraiserParams(0, coinType, 0).String()
+	} else {
+		params, err := hd.NewParamsFromPath(hdPath)
+		if err != nil {
+			return fmt.Errorf("invalid hd path: %s", err)
+		}
+
+		if c.CoinType != 0 && params.CoinType != c.CoinType {
+			return fmt.Errorf("coin type %d of hd path %s does not match coin type %d", params.CoinType, hdPath, c.CoinType)
+		}
+		coinType = params.CoinType
+	}
+
+	validatorPrefix := accountPrefix + sdktypes.PrefixValidator + sdktypes.PrefixOperator
+	consensusPrefix := accountPrefix + sdktypes.PrefixValidator + sdktypes.PrefixConsensus
+
+	sdkConfig := sdktypes.GetConfig()
+	sdkConfig.SetBech32PrefixForAccount(accountPrefix, accountPrefix+sdktypes.PrefixPublic)
+	sdkConfig.SetBech32PrefixForValidator(validatorPrefix, validatorPrefix+sdktypes.PrefixPublic)
+	sdkConfig.SetBech32PrefixForConsensusNode(consensusPrefix, consensusPrefix+sdktypes.PrefixPublic)
+	sdkConfig.SetCoinType(coinType)
+	sdkConfig.SetFullFundraiserPath(hdPath)
+	sdkConfig.Seal()
+
+	return nil
+}
@@ -3,6 +3,7 @@ package config_test
 import (
 	"testing"
 
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/require"
 
 	"github.com/b-harvest/cosmos-module-stress-test/config"
@@ -30,12 +31,48 @@ address = "localhost:9090"
 [lcd]
 address = "http://localhost:1317"
 
+[chain]
+account_prefix = "liq"
+coin_type = 118
+hd_path = "m/44'/118'/0'/0/0"
+
+[keyring]
+backend = "test"
+dir = "."
+key_name = "master"
+worker_key_prefix = "worker"
+
 [custom]
 mnemonic = "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+account_index = 0
+address_index = 10
+num_accounts = 5
+multisig_threshold = 2
+multisig_keys = 3
 gas_limit = 100000000
+estimate_gas = true
+gas_adjustment = 1.2
 fee_denom = "stake"
 fee_amount = 0
+gas_prices = "0.025stake"
 memo = ""
+sign_mode = "amino-json"
+sign_workers = 4
+target_tx_size = 2048
+broadcast_mode = "sync"
+broadcast_transport = "rpc"
+broadcast_policy = "sticky"
+max_retries = 5
+retry_base_delay = "50ms"
+retry_max_delay = "1s"
+
+[[endpoints]]
+rpc = "http://192.168.0.2:26657"
+grpc = "192.168.0.2:9090"
+
+[[endpoints]]
+rpc = "http://192.168.0.3:26657"
+lcd = "http://192.168.0.3:1317"
 `
 	cfg, err := config.ParseString([]byte(sampleConfig))
 	require.NoError(t, err)
@@ -43,4 +80,53 @@ memo = ""
 	require.Equal(t, "http://localhost:26657", cfg.RPC.Address)
 	require.Equal(t, "localhost:9090", cfg.GRPC.Address)
 	require.Equal(t, "http://localhost:1317", cfg.LCD.Address)
+	require.Equal(t, uint32(0), cfg.Custom.AccountIndex)
+	require.Equal(t, uint32(10), cfg.Custom.AddressIndex)
+	require.Equal(t, uint32(5), cfg.Custom.NumAccounts)
+	require.Equal(t, uint32(2), cfg.Custom.MultisigThreshold)
+	require.Equal(t, uint32(3), cfg.Custom.MultisigKeys)
+	require.Equal(t, "amino-json", cfg.Custom.SignMode)
+	require.Equal(t, 4, cfg.Custom.SignWorkers)
+	require.Equal(t, 2048, cfg.Custom.TargetTxSize)
+	require.False(t, cfg.Custom.FillBlocks)
+	require.Equal(t, "sync", cfg.Custom.BroadcastMode)
+	require.Equal(t, "rpc", cfg.Custom.BroadcastTransport)
+	require.Equal(t, "sticky", cfg.Custom.BroadcastPolicy)
+	require.Equal(t, 5, cfg.Custom.MaxRetries)
+	require.Equal(t, "50ms", cfg.Custom.RetryBaseDelay)
+	require.Equal(t, "1s", cfg.Custom.RetryMaxDelay)
+	require.Len(t, cfg.Endpoints, 2)
+	require.Equal(t, "http://192.168.0.2:26657", cfg.Endpoints[0].RPC)
+	require.Equal(t, "192.168.0.2:9090", cfg.Endpoints[0].GRPC)
+	require.Equal(t, "", cfg.Endpoints[0].LCD)
+	require.Equal(t, "http://192.168.0.3:1317", cfg.Endpoints[1].LCD)
+	require.True(t, cfg.Custom.EstimateGas)
+	require.Equal(t, 1.2, cfg.Custom.GasAdjustment)
+	require.Equal(t, "0.025stake", cfg.Custom.GasPrices)
+	require.Equal(t, "liq", cfg.Chain.AccountPrefix)
+	require.Equal(t, uint32(118), cfg.Chain.CoinType)
+	require.Equal(t, "m/44'/118'/0'/0/0", cfg.Chain.HDPath)
+	require.Equal(t, "test", cfg.Keyring.Backend)
+	require.Equal(t, "master", cfg.Keyring.KeyName)
+	require.Equal(t, "worker", cfg.Keyring.WorkerKeyPrefix)
+}
+
+func TestApplyChainConfig(t *testing.T) {
+	// invalid configs are rejected before the global config is modified
+	err := (&config.ChainConfig{HDPath: "m/44'/118'/0'"}).Apply()
+	require.Error(t, err)
+
+	err = (&config.ChainConfig{CoinType: 60, HDPath: "m/44'/118'/0'/0/0"}).Apply()
+	require.Error(t, err)
+
+	err = (&config.ChainConfig{AccountPrefix: "liq", CoinType: 60}).Apply()
+	require.NoError(t, err)
+
+	sdkConfig := sdktypes.GetConfig()
+	require.Equal(t, "liq", sdkConfig.GetBech32AccountAddrPrefix())
+	require.Equal(t, "liqpub", sdkConfig.GetBech32AccountPubPrefix())
+	require.Equal(t, "liqvaloper", sdkConfig.GetBech32ValidatorAddrPrefix())
+	require.Equal(t, "liqvalcons", sdkConfig.GetBech32ConsensusAddrPrefix())
+	require.Equal(t, uint32(60), sdkConfig.GetCoinType())
+	require.Equal(t, "m/44'/60'/0'/0/0", sdkConfig.GetFullFundraiserPath())
 }
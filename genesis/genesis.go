@@ -0,0 +1,81 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/b-harvest/cosmos-module-stress-test/codec"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/cosmos-sdk/x/genutil"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+)
+
+// AddGenesisAccounts adds a base account holding the given coins for every address to the genesis file.
+// It is equivalent to running the add-genesis-account command of the chain for every address.
+func AddGenesisAccounts(genFile string, addresses []string, coins sdktypes.Coins) error {
+	cdc := codec.AppCodec
+
+	appState, genDoc, err := genutiltypes.GenesisStateFromGenFile(genFile)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal genesis state: %s", err)
+	}
+
+	authGenState := authtypes.GetGenesisStateFromAppState(cdc, appState)
+
+	accs, err := authtypes.UnpackAccounts(authGenState.Accounts)
+	if err != nil {
+		return fmt.Errorf("failed to get accounts from any: %s", err)
+	}
+
+	bankGenState := banktypes.GetGenesisStateFromAppState(cdc, appState)
+
+	for _, address := range addresses {
+		accAddr, err := sdktypes.AccAddressFromBech32(address)
+		if err != nil {
+			return err
+		}
+
+		if accs.Contains(accAddr) {
+			return fmt.Errorf("cannot add account at existing address %s", address)
+		}
+
+		accs = append(accs, authtypes.NewBaseAccount(accAddr, nil, 0, 0))
+
+		bankGenState.Balances = append(bankGenState.Balances, banktypes.Balance{Address: address, Coins: coins.Sort()})
+		bankGenState.Supply = bankGenState.Supply.Add(coins...)
+	}
+
+	accs = authtypes.SanitizeGenesisAccounts(accs)
+
+	genAccs, err := authtypes.PackAccounts(accs)
+	if err != nil {
+		return fmt.Errorf("failed to convert accounts into any's: %s", err)
+	}
+	authGenState.Accounts = genAccs
+
+	authGenStateBz, err := cdc.MarshalJSON(&authGenState)
+	if err != nil {
+		return fmt.Errorf("failed to marshal auth genesis state: %s", err)
+	}
+	appState[authtypes.ModuleName] = authGenStateBz
+
+	bankGenState.Balances = banktypes.SanitizeGenesisBalances(bankGenState.Balances)
+
+	bankGenStateBz, err := cdc.MarshalJSON(bankGenState)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bank genesis state: %s", err)
+	}
+	appState[banktypes.ModuleName] = bankGenStateBz
+
+	appStateJSON, err := json.Marshal(appState)
+	if err != nil {
+		return fmt.Errorf("failed to marshal application genesis state: %s", err)
+	}
+
+	genDoc.AppState = appStateJSON
+
+	return genutil.ExportGenesisFile(genDoc, genFile)
+}
@@ -0,0 +1,61 @@
+package genesis_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/codec"
+	"github.com/b-harvest/cosmos-module-stress-test/genesis"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+func TestMain(m *testing.M) {
+	codec.SetCodec()
+
+	os.Exit(m.Run())
+}
+
+func TestAddGenesisAccounts(t *testing.T) {
+	genFile := filepath.Join(t.TempDir(), "genesis.json")
+
+	genDoc := &tmtypes.GenesisDoc{
+		ChainID:  "localnet",
+		AppState: []byte("{}"),
+	}
+	require.NoError(t, genDoc.SaveAs(genFile))
+
+	addresses := []string{
+		"cosmos1zaavvzxez0elundtn32qnk9lkm8kmcszzsv80v",
+		"cosmos1mzgucqnfr2l8cj5apvdpllhzt4zeuh2cshz5xu",
+	}
+	coins := sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 100), sdktypes.NewInt64Coin("uatom", 50))
+
+	err := genesis.AddGenesisAccounts(genFile, addresses, coins)
+	require.NoError(t, err)
+
+	appState, _, err := genutiltypes.GenesisStateFromGenFile(genFile)
+	require.NoError(t, err)
+
+	authGenState := authtypes.GetGenesisStateFromAppState(codec.AppCodec, appState)
+	accs, err := authtypes.UnpackAccounts(authGenState.Accounts)
+	require.NoError(t, err)
+	require.Len(t, accs, 2)
+
+	bankGenState := banktypes.GetGenesisStateFromAppState(codec.AppCodec, appState)
+	require.Len(t, bankGenState.Balances, 2)
+	require.Equal(t, coins, bankGenState.Balances[0].Coins)
+	require.Equal(t, coins.Add(coins...), bankGenState.Supply)
+
+	// accounts cannot be added twice
+	err = genesis.AddGenesisAccounts(genFile, addresses[:1], coins)
+	require.Error(t, err)
+}
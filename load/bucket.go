@@ -0,0 +1,63 @@
+package load
+
+import (
+	"time"
+)
+
+// TokenBucket paces operations at a rate. Tokens accrue at the rate up to the burst, and every operation takes one.
+//
+// The bucket keeps the time the next token becomes available instead of counting tokens, so a token taken from
+// an empty bucket is borrowed from the future and the time it becomes available is the time the operation is
+// scheduled at. The schedule therefore only depends on the start time and the rate, and not on when the caller
+// happens to take the tokens, as long as the caller falls behind by no more than the burst. The tokens of a caller
+// that falls further behind are dropped, and Take reports how many.
+type TokenBucket struct {
+	interval time.Duration
+	burst    time.Duration // how far the next token may lag behind the time the tokens are taken at
+	next     time.Time
+}
+
+// NewTokenBucket returns new TokenBucket object that starts with a single token at the start time.
+func NewTokenBucket(rate float64, burst int, start time.Time) *TokenBucket {
+	b := &TokenBucket{next: start}
+	b.SetRate(rate, burst)
+
+	return b
+}
+
+// SetRate sets the rate and the burst of the tokens taken from now on. The rate must be positive.
+func (b *TokenBucket) SetRate(rate float64, burst int) {
+	b.SetInterval(time.Duration(float64(time.Second)/rate), burst)
+}
+
+// SetInterval sets the interval between the tokens and the burst of the tokens taken from now on.
+// The interval must be positive.
+func (b *TokenBucket) SetInterval(interval time.Duration, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+
+	b.interval = interval
+	b.burst = time.Duration(burst-1) * b.interval
+}
+
+// Next returns the time the next token becomes available unless the caller falls behind by more than the burst.
+func (b *TokenBucket) Next() time.Time {
+	return b.next
+}
+
+// Take takes a token at the time and returns the time the token becomes available, which is the scheduled time
+// of the operation, and the number of tokens dropped before it because they accrued beyond the burst.
+// The scheduled time is in the future when the bucket is empty and the caller must wait for it.
+func (b *TokenBucket) Take(now time.Time) (time.Time, int) {
+	var dropped int
+	if earliest := now.Add(-b.burst); b.next.Before(earliest) {
+		dropped = int(earliest.Sub(b.next) / b.interval)
+		b.next = earliest
+	}
+
+	scheduled := b.next
+	b.next = b.next.Add(b.interval)
+
+	return scheduled, dropped
+}
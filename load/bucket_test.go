@@ -0,0 +1,59 @@
+package load_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/load"
+)
+
+func TestTokenBucketSchedule(t *testing.T) {
+	start := time.Unix(0, 0)
+	b := load.NewTokenBucket(10, 1, start)
+
+	// a caller that waits for every token follows the schedule from the start time
+	now := start
+	for i := 0; i < 5; i++ {
+		scheduled, dropped := b.Take(now)
+		require.Equal(t, start.Add(time.Duration(i)*100*time.Millisecond), scheduled)
+		require.Equal(t, 0, dropped)
+		now = scheduled
+	}
+}
+
+func TestTokenBucketCatchUp(t *testing.T) {
+	start := time.Unix(0, 0)
+	b := load.NewTokenBucket(10, 5, start)
+	scheduled, _ := b.Take(start)
+	require.Equal(t, start, scheduled)
+
+	// a caller that falls behind gets the tokens scheduled in the past up to the burst
+	now := start.Add(300 * time.Millisecond)
+	for i := 1; i <= 4; i++ {
+		scheduled, dropped := b.Take(now)
+		require.Equal(t, start.Add(time.Duration(i)*100*time.Millisecond), scheduled)
+		require.Equal(t, 0, dropped)
+	}
+}
+
+func TestTokenBucketBurst(t *testing.T) {
+	start := time.Unix(0, 0)
+	b := load.NewTokenBucket(10, 2, start)
+	scheduled, _ := b.Take(start)
+	require.Equal(t, start, scheduled)
+
+	// the tokens beyond the burst are dropped, so the schedule restarts from the burst
+	now := start.Add(time.Second)
+	scheduled, dropped := b.Take(now)
+	require.Equal(t, start.Add(900*time.Millisecond), scheduled)
+	require.Equal(t, 8, dropped)
+
+	scheduled, dropped = b.Take(now)
+	require.Equal(t, now, scheduled)
+	require.Equal(t, 0, dropped)
+
+	scheduled, _ = b.Take(now)
+	require.Equal(t, now.Add(100*time.Millisecond), scheduled)
+}
@@ -0,0 +1,495 @@
+// Package load runs operations at a target rate and reports the achieved rate and latency every second.
+package load
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// queueSize is the number of scheduled operations a worker holds. The operations scheduled for a worker whose
+// queue is full are dropped rather than blocking the dispatcher, which would delay the operations of every worker.
+const queueSize = 1024
+
+// Config is the rate and the stop conditions of a run.
+type Config struct {
+	Rate     float64       // target operations per second of a run without a profile
+	Profile  Profile       // steps of the target rate the run follows; the run follows the rate when it is empty
+	Burst    int           // operations dispatched at once to catch up with the schedule; defaults to one second of operations
+	Duration time.Duration // the run stops scheduling operations after the duration unless it is 0
+	Count    int           // the run stops scheduling operations after the count unless it is 0
+	Workers  int           // number of workers running the operations
+}
+
+// Validate validates the config of a run.
+func (c Config) Validate() error {
+	if len(c.Profile) > 0 {
+		if err := c.Profile.Validate(); err != nil {
+			return err
+		}
+	} else if c.Rate <= 0 {
+		return fmt.Errorf("rate must be positive: %f", c.Rate)
+	}
+
+	if c.Burst < 0 {
+		return fmt.Errorf("burst must not be negative: %d", c.Burst)
+	}
+
+	if c.Duration < 0 || c.Count < 0 {
+		return fmt.Errorf("duration and count must not be negative")
+	}
+
+	if c.Duration == 0 && c.Count == 0 && c.profile().Duration() == 0 {
+		return fmt.Errorf("either duration or count must be set")
+	}
+
+	if c.Workers <= 0 {
+		return fmt.Errorf("workers must be positive: %d", c.Workers)
+	}
+
+	return nil
+}
+
+// profile returns the profile of the run, which is the constant rate when no profile is given.
+func (c Config) profile() Profile {
+	if len(c.Profile) > 0 {
+		return c.Profile
+	}
+
+	return Constant(c.Rate, 0)
+}
+
+// Job is an operation scheduled in a run.
+type Job struct {
+	Worker    int       // worker the operation runs on
+	Step      int       // index of the step of the profile the operation is scheduled in
+	Scheduled time.Time // time the operation is scheduled at
+
+	due time.Time // time the next operation is scheduled at; the operation is late when it starts after it
+}
+
+// Op runs the operation of the job. The operations of a worker run one by one in the order they are scheduled.
+type Op func(ctx context.Context, job Job) error
+
+// Report is the results of the operations of an interval of a run.
+// The latency of an operation is measured from the time it was scheduled at rather than the time it started,
+// so that the time an operation waited for the previous operations of its worker is not omitted.
+// When the run falls behind its schedule, the operations are counted as late or dropped rather than
+// silently scheduled later, so that the report shows how far the achieved load is from the target.
+type Report struct {
+	Step       int           // index of the step of the profile at the end of the interval
+	Elapsed    time.Duration // time from the start of the run to the end of the interval
+	Interval   time.Duration
+	Target     float64 // target operations per second
+	Scheduled  int     // operations scheduled in the interval, including the dropped ones
+	Completed  int     // operations completed in the interval, including the failed ones
+	Failed     int     // operations failed in the interval
+	Late       int     // operations started in the interval after the next operation was scheduled
+	Dropped    int     // operations not run because the dispatcher or the queue of their worker fell behind
+	Pending    int     // operations scheduled but neither completed nor dropped at the end of the interval
+	AvgLatency time.Duration
+	MaxLatency time.Duration
+}
+
+// Rate returns the completed operations per second of the interval.
+func (r Report) Rate() float64 {
+	if r.Interval <= 0 {
+		return 0
+	}
+
+	return float64(r.Completed) / r.Interval.Seconds()
+}
+
+// Result is the report of a whole run and the reports of the operations scheduled in every step of its profile.
+type Result struct {
+	Total Report
+	Steps []Report
+}
+
+// tally accumulates the results of operations into a report.
+type tally struct {
+	report  Report
+	latency time.Duration // total latency of the completed operations
+}
+
+func (t *tally) complete(latency time.Duration, err error) {
+	t.report.Completed++
+	if err != nil {
+		t.report.Failed++
+	}
+	if latency > t.report.MaxLatency {
+		t.report.MaxLatency = latency
+	}
+	t.latency += latency
+}
+
+// result returns the report with the average latency and the operations pending.
+func (t *tally) result() Report {
+	report := t.report
+	report.Pending = report.Scheduled - report.Completed - report.Dropped
+	if report.Completed > 0 {
+		report.AvgLatency = t.latency / time.Duration(report.Completed)
+	}
+
+	return report
+}
+
+// recorder accumulates the results of the operations of the current interval, of every step and of the whole run.
+type recorder struct {
+	start time.Time
+
+	mu       sync.Mutex
+	last     time.Time
+	step     int
+	target   float64
+	interval tally
+	total    tally
+	steps    []tally
+}
+
+func (r *recorder) schedule(job Job, target float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.step = job.Step
+	r.target = target
+	r.interval.report.Scheduled++
+	r.total.report.Scheduled++
+	r.steps[job.Step].report.Scheduled++
+}
+
+// cancel removes an operation that was queued when the run was cancelled from the run,
+// which is still counted in the interval it was scheduled in.
+func (r *recorder) cancel(job Job) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.total.report.Scheduled--
+	r.steps[job.Step].report.Scheduled--
+}
+
+// drop records an operation scheduled in the run that is not run because the run fell behind its schedule.
+func (r *recorder) drop(job Job, target float64, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.step = job.Step
+	r.target = target
+	for _, t := range []*tally{&r.interval, &r.total, &r.steps[job.Step]} {
+		t.report.Scheduled += count
+		t.report.Dropped += count
+	}
+}
+
+// late records an operation that started after the next operation was scheduled.
+func (r *recorder) late(job Job) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interval.report.Late++
+	r.total.report.Late++
+	r.steps[job.Step].report.Late++
+}
+
+func (r *recorder) complete(job Job, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interval.complete(latency, err)
+	r.total.complete(latency, err)
+	r.steps[job.Step].complete(latency, err)
+}
+
+// flush returns the report of the interval ending at the time and starts the next interval.
+func (r *recorder) flush(now time.Time) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := r.interval.result()
+	report.Step = r.step
+	report.Elapsed = now.Sub(r.start)
+	report.Interval = now.Sub(r.last)
+	report.Target = r.target
+	report.Pending = r.total.report.Scheduled - r.total.report.Completed - r.total.report.Dropped
+
+	r.last = now
+	r.interval = tally{}
+
+	return report
+}
+
+// report calls onReport with the report of every second until the returned function is called,
+// which calls onReport with the report of the last partial second unless nothing happened in it.
+func (r *recorder) report(onReport func(Report)) func() {
+	done := make(chan struct{})
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				onReport(r.flush(now))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-reported
+
+		if last := r.flush(time.Now()); last.Scheduled > 0 || last.Completed > 0 {
+			onReport(last)
+		}
+	}
+}
+
+// result returns the result of the whole run ending at the time.
+// The target of a step is its mean rate.
+func (r *recorder) result(profile Profile, now time.Time) Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elapsed := now.Sub(r.start)
+
+	total := r.total.result()
+	total.Step = r.step
+	total.Elapsed = elapsed
+	total.Interval = elapsed
+
+	steps := make([]Report, 0, len(profile))
+	var start time.Duration
+	for i, s := range profile {
+		if i > 0 && start >= elapsed {
+			break
+		}
+
+		// the step ends with the run when the run stops in it
+		end := start + s.Duration
+		if s.Duration == 0 || end > elapsed {
+			end = elapsed
+		}
+
+		step := r.steps[i].result()
+		step.Step = i
+		step.Target = s.Target()
+		step.Elapsed = end
+		step.Interval = end - start
+		steps = append(steps, step)
+
+		start += s.Duration
+	}
+
+	// the target of the whole run is the mean of the targets of its steps over time
+	for _, step := range steps {
+		if elapsed > 0 {
+			total.Target += step.Target * step.Interval.Seconds() / elapsed.Seconds()
+		}
+	}
+
+	return Result{Total: total, Steps: steps}
+}
+
+// Run runs the operations open-loop at the rate or the profile of the config until one of its stop conditions is met,
+// the profile ends or the context is done, and returns the result of the run after every scheduled operation
+// completed. The operations are scheduled by a token bucket from the start time and assigned to the workers in turn,
+// and onReport is called with the report of every second of the run and of the last partial second.
+//
+// The schedule does not wait for the operations: when the workers fall behind, the scheduled operations queue up
+// and the report shows them as pending with the latency they accumulate, and as late when they start after the next
+// operation was scheduled. The operations scheduled for a worker whose queue is full, and the tokens the dispatcher
+// falls behind by more than the burst, are dropped and counted in the report instead of delaying the schedule.
+func Run(ctx context.Context, cfg Config, op Op, onReport func(Report)) (Result, error) {
+	if err := cfg.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	profile := cfg.profile()
+
+	start := time.Now()
+	rec := &recorder{start: start, last: start, steps: make([]tally, len(profile))}
+
+	var wg sync.WaitGroup
+	queues := make([]chan Job, cfg.Workers)
+	for i := range queues {
+		queues[i] = make(chan Job, queueSize)
+
+		wg.Add(1)
+		go func(queue <-chan Job) {
+			defer wg.Done()
+
+			for job := range queue {
+				// the operations queued when the run is cancelled are removed rather than failed
+				if ctx.Err() != nil {
+					rec.cancel(job)
+					continue
+				}
+
+				if time.Now().After(job.due) {
+					rec.late(job)
+				}
+
+				err := op(ctx, job)
+				rec.complete(job, time.Since(job.Scheduled), err)
+			}
+		}(queues[i])
+	}
+
+	stopReports := rec.report(onReport)
+
+	bucket := &TokenBucket{next: start}
+	timer := time.NewTimer(0)
+	<-timer.C
+
+dispatch:
+	for i := 0; cfg.Count == 0 || i < cfg.Count; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// the interval to the next token is the time the target rate accrues an operation from the time the token
+		// becomes available, which also paces a rate rising from 0
+		elapsed := bucket.Next().Sub(start)
+		_, rate, ok := profile.At(elapsed)
+		if !ok {
+			break
+		}
+		interval := profile.interval(elapsed)
+
+		burst := cfg.Burst
+		if burst == 0 {
+			burst = int(time.Second / interval)
+		}
+		bucket.SetInterval(interval, burst)
+
+		scheduled, dropped := bucket.Take(time.Now())
+		if cfg.Duration > 0 && scheduled.Sub(start) >= cfg.Duration {
+			break
+		}
+
+		step, _, ok := profile.At(scheduled.Sub(start))
+		if !ok {
+			break
+		}
+
+		if dropped > 0 {
+			rec.drop(Job{Step: step, Scheduled: scheduled}, rate, dropped)
+		}
+
+		if wait := time.Until(scheduled); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				break dispatch
+			}
+		}
+
+		job := Job{Worker: i % cfg.Workers, Step: step, Scheduled: scheduled, due: scheduled.Add(interval)}
+		select {
+		case queues[job.Worker] <- job:
+			rec.schedule(job, rate)
+		default:
+			rec.drop(job, rate, 1)
+		}
+	}
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	stopReports()
+
+	return rec.result(profile, time.Now()), nil
+}
+
+// ClosedConfig is the workers and the stop conditions of a closed-loop run.
+type ClosedConfig struct {
+	Workers  int           // number of workers running operations one after another
+	Duration time.Duration // the workers stop starting operations after the duration unless it is 0
+	Count    int           // the workers stop starting operations after the count unless it is 0
+}
+
+// Validate validates the config of a closed-loop run.
+func (c ClosedConfig) Validate() error {
+	if c.Workers <= 0 {
+		return fmt.Errorf("workers must be positive: %d", c.Workers)
+	}
+
+	if c.Duration < 0 || c.Count < 0 {
+		return fmt.Errorf("duration and count must not be negative")
+	}
+
+	if c.Duration == 0 && c.Count == 0 {
+		return fmt.Errorf("either duration or count must be set")
+	}
+
+	return nil
+}
+
+// RunClosed runs the operations closed-loop: every worker starts an operation as soon as its previous one completed,
+// until one of the stop conditions of the config is met or the context is done, and returns the result of the run
+// after the operations in flight completed. The rate of a closed-loop run is the throughput the workers achieve,
+// so the reports have no target rate, and the latency of an operation is the time it took.
+// onReport is called with the report of every second of the run and of the last partial second.
+func RunClosed(ctx context.Context, cfg ClosedConfig, op Op, onReport func(Report)) (Result, error) {
+	if err := cfg.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	profile := Constant(0, 0)
+
+	start := time.Now()
+	rec := &recorder{start: start, last: start, steps: make([]tally, len(profile))}
+	stopReports := rec.report(onReport)
+
+	var (
+		mu      sync.Mutex
+		started int
+	)
+	// claim reports whether the worker may start another operation
+	claim := func() bool {
+		if ctx.Err() != nil || (cfg.Duration > 0 && time.Since(start) >= cfg.Duration) {
+			return false
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cfg.Count > 0 && started >= cfg.Count {
+			return false
+		}
+		started++
+
+		return true
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			for claim() {
+				job := Job{Worker: worker, Scheduled: time.Now()}
+				rec.schedule(job, 0)
+
+				err := op(ctx, job)
+				rec.complete(job, time.Since(job.Scheduled), err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stopReports()
+
+	return rec.result(profile, time.Now()), nil
+}
@@ -0,0 +1,274 @@
+package load_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/load"
+)
+
+func TestConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  load.Config
+		ok   bool
+	}{
+		{"count", load.Config{Rate: 10, Count: 10, Workers: 1}, true},
+		{"duration", load.Config{Rate: 10, Duration: time.Second, Workers: 1}, true},
+		{"no rate", load.Config{Count: 10, Workers: 1}, false},
+		{"no stop condition", load.Config{Rate: 10, Workers: 1}, false},
+		{"no workers", load.Config{Rate: 10, Count: 10}, false},
+		{"negative burst", load.Config{Rate: 10, Burst: -1, Count: 10, Workers: 1}, false},
+		{"profile", load.Config{Profile: load.Profile{{Duration: time.Second, From: 1, To: 10}}, Workers: 1}, true},
+		{"unbounded profile", load.Config{Profile: load.Constant(10, 0), Workers: 1}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.ok {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRunCount(t *testing.T) {
+	var mu sync.Mutex
+	ops := make(map[int]int)
+
+	cfg := load.Config{Rate: 200, Count: 50, Workers: 3}
+	result, err := load.Run(context.Background(), cfg, func(ctx context.Context, job load.Job) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ops[job.Worker]++
+		if ops[job.Worker]%5 == 0 {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	}, func(load.Report) {})
+	require.NoError(t, err)
+
+	total := result.Total
+	require.Equal(t, 50, total.Scheduled)
+	require.Equal(t, 50, total.Completed)
+	require.Equal(t, 9, total.Failed)
+	require.Equal(t, 0, total.Pending)
+	require.Equal(t, map[int]int{0: 17, 1: 17, 2: 16}, ops)
+
+	// 50 operations at 200 per second are scheduled over 245ms
+	require.True(t, total.Elapsed >= 245*time.Millisecond)
+}
+
+func TestRunDuration(t *testing.T) {
+	var reports []load.Report
+
+	cfg := load.Config{Rate: 100, Duration: 1500 * time.Millisecond, Workers: 2}
+	result, err := load.Run(context.Background(), cfg, func(ctx context.Context, job load.Job) error {
+		return nil
+	}, func(r load.Report) {
+		reports = append(reports, r)
+	})
+	require.NoError(t, err)
+
+	total := result.Total
+	require.InDelta(t, 100, total.Target, 1e-9)
+	require.Len(t, result.Steps, 1)
+
+	// the schedule only depends on the rate and the duration, while the operations the run fell behind on
+	// are dropped rather than completed
+	require.Equal(t, 150, total.Scheduled)
+	require.Equal(t, total.Scheduled, total.Completed+total.Dropped)
+	require.Equal(t, 0, total.Pending)
+
+	require.NotEmpty(t, reports)
+	require.Equal(t, float64(100), reports[0].Target)
+
+	var completed int
+	for _, r := range reports {
+		completed += r.Completed
+	}
+	require.Equal(t, total.Completed, completed)
+}
+
+func TestRunLatencyFromSchedule(t *testing.T) {
+	// a single worker whose operations take longer than the interval falls behind the schedule,
+	// and the latency of the later operations includes the time they waited for it
+	cfg := load.Config{Rate: 100, Count: 10, Workers: 1}
+	result, err := load.Run(context.Background(), cfg, func(ctx context.Context, job load.Job) error {
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	}, func(load.Report) {})
+	require.NoError(t, err)
+
+	total := result.Total
+	require.Equal(t, 10, total.Completed)
+	require.True(t, total.MaxLatency >= 200*time.Millisecond)
+}
+
+func TestRunCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cfg := load.Config{Rate: 100, Duration: time.Minute, Workers: 1}
+	result, err := load.Run(ctx, cfg, func(ctx context.Context, job load.Job) error {
+		cancel()
+		return nil
+	}, func(load.Report) {})
+	require.NoError(t, err)
+
+	total := result.Total
+	require.Equal(t, 1, total.Completed)
+	require.Equal(t, 0, total.Pending)
+}
+
+func TestRunProfile(t *testing.T) {
+	profile := load.Profile{
+		{Duration: 500 * time.Millisecond, From: 40, To: 40},
+		{Duration: 500 * time.Millisecond, From: 100, To: 100},
+		{Duration: 500 * time.Millisecond, From: 20, To: 60},
+	}
+
+	var mu sync.Mutex
+	steps := make(map[int]int)
+
+	cfg := load.Config{Profile: profile, Workers: 2}
+	result, err := load.Run(context.Background(), cfg, func(ctx context.Context, job load.Job) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		steps[job.Step]++
+		return nil
+	}, func(load.Report) {})
+	require.NoError(t, err)
+
+	// every step schedules its mean rate over its duration
+	require.Len(t, result.Steps, 3)
+	require.InDelta(t, 20, result.Steps[0].Scheduled, 1)
+	require.InDelta(t, 50, result.Steps[1].Scheduled, 1)
+	require.InDelta(t, 20, result.Steps[2].Scheduled, 1)
+
+	for i, step := range result.Steps {
+		require.Equal(t, i, step.Step)
+		require.Equal(t, profile[i].Target(), step.Target)
+		require.Equal(t, steps[i], step.Completed)
+		require.Equal(t, step.Scheduled, step.Completed+step.Dropped)
+	}
+	require.Equal(t, 500*time.Millisecond, result.Steps[0].Interval)
+}
+
+func TestRunProfileFromZero(t *testing.T) {
+	// a ramp from no load schedules its mean rate over its duration
+	cfg := load.Config{Profile: load.Profile{{Duration: time.Second, From: 0, To: 100}}, Workers: 1}
+	result, err := load.Run(context.Background(), cfg, func(ctx context.Context, job load.Job) error {
+		return nil
+	}, func(load.Report) {})
+	require.NoError(t, err)
+	require.InDelta(t, 50, result.Total.Scheduled, 1)
+}
+
+func TestRunClosedCount(t *testing.T) {
+	var mu sync.Mutex
+	inFlight := make(map[int]int)
+	ops := make(map[int]int)
+
+	cfg := load.ClosedConfig{Workers: 4, Count: 40}
+	result, err := load.RunClosed(context.Background(), cfg, func(ctx context.Context, job load.Job) error {
+		mu.Lock()
+		inFlight[job.Worker]++
+		if inFlight[job.Worker] > 1 {
+			mu.Unlock()
+			return fmt.Errorf("worker %d runs more than one operation", job.Worker)
+		}
+		ops[job.Worker]++
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight[job.Worker]--
+		mu.Unlock()
+		return nil
+	}, func(load.Report) {})
+	require.NoError(t, err)
+
+	// every worker runs one operation at a time
+	require.True(t, len(ops) <= 4)
+
+	total := result.Total
+	require.Equal(t, 40, total.Scheduled)
+	require.Equal(t, 40, total.Completed)
+	require.Equal(t, 0, total.Pending)
+	require.Equal(t, 0, total.Failed)
+	require.Equal(t, float64(0), total.Target)
+	require.True(t, total.AvgLatency >= 10*time.Millisecond)
+}
+
+func TestRunClosedDuration(t *testing.T) {
+	var reports []load.Report
+
+	cfg := load.ClosedConfig{Workers: 2, Duration: 1200 * time.Millisecond}
+	result, err := load.RunClosed(context.Background(), cfg, func(ctx context.Context, job load.Job) error {
+		time.Sleep(100 * time.Millisecond)
+		if job.Worker == 1 {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	}, func(r load.Report) {
+		reports = append(reports, r)
+	})
+	require.NoError(t, err)
+
+	// two workers complete at most an operation every 100ms each until the duration passed,
+	// and at least the one each started right away
+	total := result.Total
+	require.True(t, total.Completed >= 2)
+	require.True(t, total.Completed <= 26)
+	require.InDelta(t, total.Completed/2, total.Failed, 1)
+	require.NotEmpty(t, reports)
+
+	var completed int
+	for _, r := range reports {
+		completed += r.Completed
+	}
+	require.Equal(t, total.Completed, completed)
+}
+
+func TestRunClosedValidate(t *testing.T) {
+	_, err := load.RunClosed(context.Background(), load.ClosedConfig{Workers: 1}, func(context.Context, load.Job) error {
+		return nil
+	}, func(load.Report) {})
+	require.Error(t, err)
+
+	_, err = load.RunClosed(context.Background(), load.ClosedConfig{Count: 1}, func(context.Context, load.Job) error {
+		return nil
+	}, func(load.Report) {})
+	require.Error(t, err)
+}
+
+func TestRunQueueFull(t *testing.T) {
+	// a worker stuck on its first operation does not stall the dispatcher: the operations its full queue
+	// cannot hold are dropped, and the queued ones start late
+	var once sync.Once
+
+	cfg := load.Config{Rate: 5000, Count: 1500, Workers: 1}
+	result, err := load.Run(context.Background(), cfg, func(ctx context.Context, job load.Job) error {
+		once.Do(func() {
+			time.Sleep(time.Second)
+		})
+		return nil
+	}, func(load.Report) {})
+	require.NoError(t, err)
+
+	total := result.Total
+	require.Equal(t, 1500, total.Scheduled)
+	require.True(t, total.Dropped > 0)
+	require.True(t, total.Late > 0)
+	require.Equal(t, total.Scheduled, total.Completed+total.Dropped)
+	require.Equal(t, 0, total.Pending)
+}
@@ -0,0 +1,404 @@
+package load
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSteps bounds the number of steps of a profile.
+const MaxSteps = 10000
+
+// Profile kinds of the profile specs.
+const (
+	ProfileRamp   = "ramp"
+	ProfileStairs = "stairs"
+	ProfileSpike  = "spike"
+	ProfileSine   = "sine"
+)
+
+// Step is a period of a run whose target rate changes linearly from From to To over its duration.
+// A step of no duration never ends, which is only allowed for the last step of a profile.
+// Either rate of a step may be 0, e.g. to ramp up from no load, but not both.
+type Step struct {
+	Duration time.Duration
+	From     float64
+	To       float64
+}
+
+// Rate returns the target rate of the step at the offset from its start.
+func (s Step) Rate(offset time.Duration) float64 {
+	if s.Duration <= 0 || s.From == s.To {
+		return s.From
+	}
+
+	return s.From + (s.To-s.From)*offset.Seconds()/s.Duration.Seconds()
+}
+
+// interval returns the time from the offset to the next operation of the step, over which the target rate accrues
+// a single operation. It is the time to the end of the step when the rate does not accrue an operation before it.
+func (s Step) interval(offset time.Duration) time.Duration {
+	r0 := s.Rate(offset)
+
+	var slope float64
+	if s.Duration > 0 {
+		slope = (s.To - s.From) / s.Duration.Seconds()
+	}
+
+	// the interval d solves r0*d + slope*d*d/2 = 1
+	disc := r0*r0 + 2*slope
+	if disc < 0 {
+		return s.Duration - offset
+	}
+
+	interval := time.Duration(2 / (r0 + math.Sqrt(disc)) * float64(time.Second))
+	if interval <= 0 {
+		return 1
+	}
+
+	return interval
+}
+
+// Target returns the mean target rate of the step.
+func (s Step) Target() float64 {
+	return (s.From + s.To) / 2
+}
+
+// Profile is the steps of the target rate a run follows one after another.
+type Profile []Step
+
+// Constant returns the profile of a constant rate for the duration, which never ends when the duration is 0.
+func Constant(rate float64, duration time.Duration) Profile {
+	return Profile{{Duration: duration, From: rate, To: rate}}
+}
+
+// Validate validates the steps of the profile.
+func (p Profile) Validate() error {
+	if len(p) == 0 {
+		return fmt.Errorf("profile must have at least one step")
+	}
+
+	if len(p) > MaxSteps {
+		return fmt.Errorf("profile must have no more than %d steps: %d", MaxSteps, len(p))
+	}
+
+	for i, s := range p {
+		if s.From < 0 || s.To < 0 || (s.From == 0 && s.To == 0) {
+			return fmt.Errorf("rates of step %d must not be negative and one of them must be positive: %f, %f", i+1, s.From, s.To)
+		}
+
+		if s.Duration < 0 || (s.Duration == 0 && i != len(p)-1) {
+			return fmt.Errorf("duration of step %d must be positive: %s", i+1, s.Duration)
+		}
+	}
+
+	return nil
+}
+
+// Duration returns the total duration of the steps, which is 0 when the profile never ends.
+func (p Profile) Duration() time.Duration {
+	var total time.Duration
+	for _, s := range p {
+		if s.Duration == 0 {
+			return 0
+		}
+		total += s.Duration
+	}
+
+	return total
+}
+
+// At returns the index of the step and the target rate at the elapsed time of a run.
+// It returns false when the profile ended before the elapsed time.
+func (p Profile) At(elapsed time.Duration) (int, float64, bool) {
+	var start time.Duration
+	for i, s := range p {
+		if s.Duration == 0 || elapsed < start+s.Duration {
+			return i, s.Rate(elapsed - start), true
+		}
+		start += s.Duration
+	}
+
+	return 0, 0, false
+}
+
+// interval returns the time from the elapsed time of a run to the next operation in the step at the elapsed time.
+// The profile must not have ended before the elapsed time.
+func (p Profile) interval(elapsed time.Duration) time.Duration {
+	var start time.Duration
+	for _, s := range p {
+		if s.Duration == 0 || elapsed < start+s.Duration {
+			return s.interval(elapsed - start)
+		}
+		start += s.Duration
+	}
+
+	return 0
+}
+
+// ParseProfile parses a profile spec of the form kind:key=value,... where the kind and its keys are one of:
+//
+//	ramp:from=10,to=500,duration=10m[,steps=10]      rises linearly from a rate to a rate in a number of steps
+//	stairs:from=50,to=500,step=50,hold=1m             holds every rate from a rate to a rate by the step
+//	spike:base=50,peak=1000,every=1m,length=5s[,count=1]
+//	                                                  holds the base and bursts to the peak for the length once
+//	                                                  every period, and holds the base once more to recover
+//	sine:base=200,amplitude=150,period=2m,duration=10m[,steps=8]
+//	                                                  follows a sine wave around the base in a number of steps
+//	                                                  per period
+func ParseProfile(spec string) (Profile, error) {
+	kind, paramsStr := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		kind, paramsStr = spec[:i], spec[i+1:]
+	}
+
+	params := newProfileParams()
+	for _, param := range strings.Split(paramsStr, ",") {
+		param = strings.TrimSpace(param)
+		if param == "" {
+			continue
+		}
+
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid profile parameter %s; must be key=value", param)
+		}
+		params.values[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	var (
+		p   Profile
+		err error
+	)
+	switch kind {
+	case ProfileRamp:
+		p, err = parseRamp(params)
+	case ProfileStairs:
+		p, err = parseStairs(params)
+	case ProfileSpike:
+		p, err = parseSpike(params)
+	case ProfileSine:
+		p, err = parseSine(params)
+	default:
+		return nil, fmt.Errorf("unsupported profile %s; must be one of %s, %s, %s or %s", kind, ProfileRamp, ProfileStairs, ProfileSpike, ProfileSine)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s profile: %s", kind, err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func parseRamp(params *profileParams) (Profile, error) {
+	from, to := params.float("from"), params.float("to")
+	duration := params.duration("duration")
+	steps := params.intOr("steps", 10)
+	if err := params.done(); err != nil {
+		return nil, err
+	}
+
+	if steps <= 0 || steps > MaxSteps {
+		return nil, fmt.Errorf("steps must be between 1 and %d: %d", MaxSteps, steps)
+	}
+
+	p := make(Profile, 0, steps)
+	for i := 0; i < steps; i++ {
+		p = append(p, Step{
+			Duration: duration / time.Duration(steps),
+			From:     from + (to-from)*float64(i)/float64(steps),
+			To:       from + (to-from)*float64(i+1)/float64(steps),
+		})
+	}
+
+	return p, nil
+}
+
+func parseStairs(params *profileParams) (Profile, error) {
+	from, to, step := params.float("from"), params.float("to"), params.float("step")
+	hold := params.duration("hold")
+	if err := params.done(); err != nil {
+		return nil, err
+	}
+
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive: %f", step)
+	}
+
+	// the rates are computed from the count of steps rather than summed, so that rounding neither drops nor adds
+	// the last step, e.g. 0.5 is the fourth rate of from=0.2,to=0.5,step=0.1 although 0.3/0.1 is below 3
+	count := math.Floor(math.Abs(to-from)/step+1e-9) + 1
+	if count > MaxSteps {
+		return nil, fmt.Errorf("stairs must have no more than %d steps: %.0f", MaxSteps, count)
+	}
+
+	if to < from {
+		step = -step
+	}
+
+	var p Profile
+	for i := 0; i < int(count); i++ {
+		rate := from + float64(i)*step
+		p = append(p, Step{Duration: hold, From: rate, To: rate})
+	}
+
+	return p, nil
+}
+
+func parseSpike(params *profileParams) (Profile, error) {
+	base, peak := params.float("base"), params.float("peak")
+	every, length := params.duration("every"), params.duration("length")
+	count := params.intOr("count", 1)
+	if err := params.done(); err != nil {
+		return nil, err
+	}
+
+	if length <= 0 || length >= every {
+		return nil, fmt.Errorf("length must be positive and shorter than every: %s", length)
+	}
+
+	if count <= 0 || 2*count+1 > MaxSteps {
+		return nil, fmt.Errorf("count must be between 1 and %d: %d", (MaxSteps-1)/2, count)
+	}
+
+	var p Profile
+	for i := 0; i < count; i++ {
+		p = append(p,
+			Step{Duration: every - length, From: base, To: base},
+			Step{Duration: length, From: peak, To: peak},
+		)
+	}
+
+	return append(p, Step{Duration: every - length, From: base, To: base}), nil
+}
+
+func parseSine(params *profileParams) (Profile, error) {
+	base, amplitude := params.float("base"), params.float("amplitude")
+	period, duration := params.duration("period"), params.duration("duration")
+	steps := params.intOr("steps", 8)
+	if err := params.done(); err != nil {
+		return nil, err
+	}
+
+	if amplitude >= base {
+		return nil, fmt.Errorf("amplitude must be less than base to keep the rate positive: %f", amplitude)
+	}
+
+	if steps <= 0 || period <= 0 {
+		return nil, fmt.Errorf("period and steps must be positive")
+	}
+
+	if count := math.Ceil(duration.Seconds() / period.Seconds() * float64(steps)); count > MaxSteps {
+		return nil, fmt.Errorf("sine must have no more than %d steps: %.0f", MaxSteps, count)
+	}
+
+	rate := func(t time.Duration) float64 {
+		return base + amplitude*math.Sin(2*math.Pi*t.Seconds()/period.Seconds())
+	}
+
+	segment := period / time.Duration(steps)
+	var p Profile
+	for start := time.Duration(0); start < duration; start += segment {
+		length := segment
+		if start+length > duration {
+			length = duration - start
+		}
+		p = append(p, Step{Duration: length, From: rate(start), To: rate(start + length)})
+	}
+
+	return p, nil
+}
+
+// profileParams is the parameters of a profile spec. The getters record the keys they read and the first error,
+// and done returns the error or an error for the keys that were never read.
+type profileParams struct {
+	values map[string]string
+	read   map[string]bool
+	err    error
+}
+
+func newProfileParams() *profileParams {
+	return &profileParams{
+		values: make(map[string]string),
+		read:   make(map[string]bool),
+	}
+}
+
+func (p *profileParams) get(key string) (string, bool) {
+	p.read[key] = true
+	v, ok := p.values[key]
+	return v, ok
+}
+
+func (p *profileParams) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *profileParams) float(key string) float64 {
+	v, ok := p.get(key)
+	if !ok {
+		p.fail(fmt.Errorf("%s must be set", key))
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		p.fail(fmt.Errorf("%s must be a number: %s", key, v))
+	}
+	return f
+}
+
+func (p *profileParams) duration(key string) time.Duration {
+	v, ok := p.get(key)
+	if !ok {
+		p.fail(fmt.Errorf("%s must be set", key))
+		return 0
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		p.fail(fmt.Errorf("%s must be a positive duration: %s", key, v))
+	}
+	return d
+}
+
+func (p *profileParams) intOr(key string, def int) int {
+	v, ok := p.get(key)
+	if !ok {
+		return def
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		p.fail(fmt.Errorf("%s must be an integer: %s", key, v))
+	}
+	return i
+}
+
+func (p *profileParams) done() error {
+	if p.err != nil {
+		return p.err
+	}
+
+	var unknown []string
+	for key := range p.values {
+		if !p.read[key] {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown parameters %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package load_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/load"
+)
+
+func TestProfileAt(t *testing.T) {
+	p := load.Profile{
+		{Duration: time.Second, From: 10, To: 20},
+		{Duration: time.Second, From: 50, To: 50},
+	}
+	require.Equal(t, 2*time.Second, p.Duration())
+
+	for _, tc := range []struct {
+		elapsed time.Duration
+		step    int
+		rate    float64
+		ok      bool
+	}{
+		{0, 0, 10, true},
+		{500 * time.Millisecond, 0, 15, true},
+		{time.Second, 1, 50, true},
+		{1999 * time.Millisecond, 1, 50, true},
+		{2 * time.Second, 0, 0, false},
+	} {
+		step, rate, ok := p.At(tc.elapsed)
+		require.Equal(t, tc.ok, ok, tc.elapsed)
+		require.Equal(t, tc.step, step, tc.elapsed)
+		require.InDelta(t, tc.rate, rate, 1e-9, tc.elapsed)
+	}
+
+	// a constant profile without a duration never ends
+	step, rate, ok := load.Constant(10, 0).At(time.Hour)
+	require.True(t, ok)
+	require.Equal(t, 0, step)
+	require.Equal(t, float64(10), rate)
+}
+
+func TestParseProfile(t *testing.T) {
+	for _, tc := range []struct {
+		spec     string
+		expected load.Profile
+	}{
+		{
+			"ramp:from=10,to=50,duration=20s,steps=4",
+			load.Profile{
+				{Duration: 5 * time.Second, From: 10, To: 20},
+				{Duration: 5 * time.Second, From: 20, To: 30},
+				{Duration: 5 * time.Second, From: 30, To: 40},
+				{Duration: 5 * time.Second, From: 40, To: 50},
+			},
+		},
+		{
+			"stairs:from=50,to=150,step=50,hold=1m",
+			load.Profile{
+				{Duration: time.Minute, From: 50, To: 50},
+				{Duration: time.Minute, From: 100, To: 100},
+				{Duration: time.Minute, From: 150, To: 150},
+			},
+		},
+		{
+			"stairs:from=150,to=50,step=100,hold=1m",
+			load.Profile{
+				{Duration: time.Minute, From: 150, To: 150},
+				{Duration: time.Minute, From: 50, To: 50},
+			},
+		},
+		{
+			"spike:base=50,peak=1000,every=1m,length=5s,count=2",
+			load.Profile{
+				{Duration: 55 * time.Second, From: 50, To: 50},
+				{Duration: 5 * time.Second, From: 1000, To: 1000},
+				{Duration: 55 * time.Second, From: 50, To: 50},
+				{Duration: 5 * time.Second, From: 1000, To: 1000},
+				{Duration: 55 * time.Second, From: 50, To: 50},
+			},
+		},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			p, err := load.ParseProfile(tc.spec)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, p)
+		})
+	}
+}
+
+func TestParseProfileSine(t *testing.T) {
+	p, err := load.ParseProfile("sine:base=200,amplitude=100,period=40s,duration=50s,steps=4")
+	require.NoError(t, err)
+
+	// four steps a period and a truncated step for the rest of the duration
+	require.Len(t, p, 5)
+	require.Equal(t, 50*time.Second, p.Duration())
+	require.Equal(t, 10*time.Second, p[4].Duration)
+
+	expected := []float64{200, 300, 200, 100, 200, 300}
+	for i, s := range p {
+		require.InDelta(t, expected[i], s.From, 1e-6)
+		require.InDelta(t, expected[i+1], s.To, 1e-6)
+	}
+}
+
+func TestParseProfileRampFromZero(t *testing.T) {
+	p, err := load.ParseProfile("ramp:from=0,to=100,duration=10s,steps=2")
+	require.NoError(t, err)
+	require.Equal(t, load.Profile{
+		{Duration: 5 * time.Second, From: 0, To: 50},
+		{Duration: 5 * time.Second, From: 50, To: 100},
+	}, p)
+}
+
+func TestParseProfileStairsFractionalStep(t *testing.T) {
+	// the rates are not summed, so that the last step is kept although 0.1+0.1+0.1 exceeds 0.3
+	p, err := load.ParseProfile("stairs:from=0.1,to=0.3,step=0.1,hold=1s")
+	require.NoError(t, err)
+	require.Len(t, p, 3)
+	require.InDelta(t, 0.3, p[2].From, 1e-9)
+
+	p, err = load.ParseProfile("stairs:from=0.2,to=0.5,step=0.1,hold=1s")
+	require.NoError(t, err)
+	require.Len(t, p, 4)
+	require.InDelta(t, 0.5, p[3].From, 1e-9)
+}
+
+func TestParseProfileInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"square:from=1",
+		"ramp:from=10,to=50",
+		"ramp:from=10,to=50,duration=1m,speed=2",
+		"ramp:from=0,to=0,duration=1m",
+		"ramp:from=-10,to=50,duration=1m",
+		"ramp:from=10,to=50,duration=1m,steps=100000",
+		"stairs:from=1,to=1000000,step=1,hold=1s",
+		"spike:base=50,peak=1000,every=1m,length=5s,count=100000",
+		"sine:base=100,amplitude=50,period=1s,duration=10h",
+		"ramp:from=ten,to=50,duration=1m",
+		"stairs:from=50,to=150,step=0,hold=1m",
+		"spike:base=50,peak=1000,every=5s,length=5s",
+		"sine:base=100,amplitude=100,period=1m,duration=1m",
+		"ramp:from",
+	} {
+		_, err := load.ParseProfile(spec)
+		require.Error(t, err, spec)
+	}
+}
@@ -10,6 +10,7 @@ MNEMONIC_1="guard cream sadness conduct invite crumble clock pudding hole grit l
 MNEMONIC_2="friend excite rough reopen cover wheel spoon convince island path clean monkey play snow number walnut pull lock shoot hurry dream divide concert discover"
 MNEMONIC_3="fuel obscure melt april direct second usual hair leave hobby beef bacon solid drum used law mercy worry fat super must ritual bring faculty"
 MNEMONIC_4="melody lonely cube ball ritual jump fabric pull pupil kit credit filter acid used festival salmon muscle first meat aisle bubble vote gorilla judge"
+NUM_ACCOUNTS=${NUM_ACCOUNTS:-0}
 GENESIS_COINS="5000000000000stake,5000000000000uatom,5000000000000ubtsg,5000000000000uregen,5000000000000uxrn,5000000000000udvpn,\
 5000000000000uxprt,5000000000000uakt,5000000000000uluna,5000000000000ungm,5000000000000ugcyb,5000000000000uiris,5000000000000xrun"
 
@@ -48,6 +49,12 @@ $BINARY --home $CHAIN_DIR/$CHAIN_ID add-genesis-account $($BINARY --home $CHAIN_
 $BINARY --home $CHAIN_DIR/$CHAIN_ID add-genesis-account $($BINARY --home $CHAIN_DIR/$CHAIN_ID keys show user2 --keyring-backend test -a) $GENESIS_COINS
 $BINARY --home $CHAIN_DIR/$CHAIN_ID add-genesis-account $($BINARY --home $CHAIN_DIR/$CHAIN_ID keys show user3 --keyring-backend test -a) $GENESIS_COINS
 
+# Add generated genesis accounts, e.g. NUM_ACCOUNTS=500 make localnet
+if [ "$NUM_ACCOUNTS" -gt 0 ]; then
+  echo "Adding $NUM_ACCOUNTS generated genesis accounts..."
+  tester genesis-accounts $NUM_ACCOUNTS $GENESIS_COINS --genesis $CHAIN_DIR/$CHAIN_ID/config/genesis.json --output $CHAIN_DIR/$CHAIN_ID/accounts.json
+fi
+
 echo "Creating and collecting gentx..."
 $BINARY --home $CHAIN_DIR/$CHAIN_ID gentx validator 1000000000stake --chain-id $CHAIN_ID --keyring-backend test
 $BINARY --home $CHAIN_DIR/$CHAIN_ID collect-gentxs
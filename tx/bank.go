@@ -0,0 +1,49 @@
+package tx
+
+import (
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// MsgSend creates send message and returns MsgSend transaction message.
+func MsgSend(fromAddr string, toAddr string, amount sdktypes.Coins) (sdktypes.Msg, error) {
+	from, err := sdktypes.AccAddressFromBech32(fromAddr)
+	if err != nil {
+		return &banktypes.MsgSend{}, err
+	}
+
+	to, err := sdktypes.AccAddressFromBech32(toAddr)
+	if err != nil {
+		return &banktypes.MsgSend{}, err
+	}
+
+	msg := banktypes.NewMsgSend(from, to, amount)
+
+	if err := msg.ValidateBasic(); err != nil {
+		return &banktypes.MsgSend{}, err
+	}
+
+	return msg, nil
+}
+
+// MsgMultiSend creates multi send message from a single sender and returns MsgMultiSend transaction message.
+// The input of the sender is the sum of the amounts of every output.
+func MsgMultiSend(fromAddr string, outputs []banktypes.Output) (sdktypes.Msg, error) {
+	from, err := sdktypes.AccAddressFromBech32(fromAddr)
+	if err != nil {
+		return &banktypes.MsgMultiSend{}, err
+	}
+
+	total := sdktypes.NewCoins()
+	for _, output := range outputs {
+		total = total.Add(output.Coins...)
+	}
+
+	msg := banktypes.NewMsgMultiSend([]banktypes.Input{banktypes.NewInput(from, total)}, outputs)
+
+	if err := msg.ValidateBasic(); err != nil {
+		return &banktypes.MsgMultiSend{}, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,32 @@
+package tx_test
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func TestMsgMultiSend(t *testing.T) {
+	sender := "cosmos1zaavvzxez0elundtn32qnk9lkm8kmcszzsv80v"
+
+	outputs := []banktypes.Output{
+		banktypes.NewOutput(sdktypes.AccAddress([]byte("receiver1___________")), sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10))),
+		banktypes.NewOutput(sdktypes.AccAddress([]byte("receiver2___________")), sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 5), sdktypes.NewInt64Coin("uatom", 3))),
+	}
+
+	msg, err := tx.MsgMultiSend(sender, outputs)
+	require.NoError(t, err)
+
+	multiSend := msg.(*banktypes.MsgMultiSend)
+	require.Len(t, multiSend.Inputs, 1)
+	require.Equal(t, sender, multiSend.Inputs[0].Address)
+	require.Equal(t, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 15), sdktypes.NewInt64Coin("uatom", 3)), multiSend.Inputs[0].Coins)
+
+	_, err = tx.MsgMultiSend(sender, nil)
+	require.Error(t, err)
+}
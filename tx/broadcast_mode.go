@@ -0,0 +1,30 @@
+package tx
+
+import (
+	"fmt"
+
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+const (
+	// BroadcastModeAsync returns right after the transaction is submitted to the node, without its CheckTx result.
+	BroadcastModeAsync = "async"
+	// BroadcastModeSync returns the CheckTx result of the transaction.
+	BroadcastModeSync = "sync"
+	// BroadcastModeBlock returns the DeliverTx result of the transaction once it is committed.
+	BroadcastModeBlock = "block"
+)
+
+// ParseBroadcastMode parses the broadcast mode of the config. The async mode is the default for the stress testing.
+func ParseBroadcastMode(mode string) (sdktx.BroadcastMode, error) {
+	switch mode {
+	case "", BroadcastModeAsync:
+		return sdktx.BroadcastMode_BROADCAST_MODE_ASYNC, nil
+	case BroadcastModeSync:
+		return sdktx.BroadcastMode_BROADCAST_MODE_SYNC, nil
+	case BroadcastModeBlock:
+		return sdktx.BroadcastMode_BROADCAST_MODE_BLOCK, nil
+	default:
+		return sdktx.BroadcastMode_BROADCAST_MODE_UNSPECIFIED, fmt.Errorf("unsupported broadcast mode %s; must be one of %s, %s or %s", mode, BroadcastModeAsync, BroadcastModeSync, BroadcastModeBlock)
+	}
+}
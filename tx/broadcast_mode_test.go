@@ -0,0 +1,27 @@
+package tx_test
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+func TestParseBroadcastMode(t *testing.T) {
+	for mode, expected := range map[string]sdktx.BroadcastMode{
+		"":      sdktx.BroadcastMode_BROADCAST_MODE_ASYNC,
+		"async": sdktx.BroadcastMode_BROADCAST_MODE_ASYNC,
+		"sync":  sdktx.BroadcastMode_BROADCAST_MODE_SYNC,
+		"block": sdktx.BroadcastMode_BROADCAST_MODE_BLOCK,
+	} {
+		broadcastMode, err := tx.ParseBroadcastMode(mode)
+		require.NoError(t, err)
+		require.Equal(t, expected, broadcastMode)
+	}
+
+	_, err := tx.ParseBroadcastMode("commit")
+	require.Error(t, err)
+}
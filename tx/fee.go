@@ -0,0 +1,48 @@
+package tx
+
+import (
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Fee returns the fee of a transaction of the gas limit.
+// The fee is the gas limit times the gas prices when gas prices are set, and the fixed fees otherwise.
+func (t *Transaction) Fee(gasLimit uint64) sdktypes.Coins {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.GasPrices.IsZero() {
+		return t.Fees
+	}
+
+	gas := sdktypes.NewDec(int64(gasLimit))
+
+	fees := make(sdktypes.Coins, 0, len(t.GasPrices))
+	for _, gasPrice := range t.GasPrices {
+		// the fee is rounded up so that it is never below the gas price
+		fee := gasPrice.Amount.Mul(gas).Ceil().RoundInt()
+		fees = append(fees, sdktypes.NewCoin(gasPrice.Denom, fee))
+	}
+
+	return sdktypes.NewCoins(fees...)
+}
+
+// RaiseGasPrices multiplies the gas prices by the factor.
+func (t *Transaction) RaiseGasPrices(factor sdktypes.Dec) sdktypes.DecCoins {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	gasPrices := make(sdktypes.DecCoins, 0, len(t.GasPrices))
+	for _, gasPrice := range t.GasPrices {
+		gasPrices = append(gasPrices, sdktypes.NewDecCoinFromDec(gasPrice.Denom, gasPrice.Amount.Mul(factor)))
+	}
+	t.GasPrices = gasPrices
+
+	return gasPrices
+}
+
+// IsInsufficientFee returns true when the transaction was rejected with the sdk ErrInsufficientFee error,
+// e.g. because its fee is below the minimum gas prices of the node.
+func IsInsufficientFee(resp *sdktypes.TxResponse) bool {
+	return resp.Codespace == sdkerrors.RootCodespace && resp.Code == sdkerrors.ErrInsufficientFee.ABCICode()
+}
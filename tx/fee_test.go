@@ -0,0 +1,45 @@
+package tx_test
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestFee(t *testing.T) {
+	fees := sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10))
+
+	transaction := tx.NewTransaction(c, "localnet", 200000, fees, "")
+	require.Equal(t, fees, transaction.Fee(200000))
+
+	gasPrices, err := sdktypes.ParseDecCoins("0.025stake,0.0001uatom")
+	require.NoError(t, err)
+	transaction.GasPrices = gasPrices
+
+	// fees are rounded up
+	require.Equal(t, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 5000), sdktypes.NewInt64Coin("uatom", 20)), transaction.Fee(200000))
+	require.Equal(t, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 1), sdktypes.NewInt64Coin("uatom", 1)), transaction.Fee(1))
+
+	raised := transaction.RaiseGasPrices(sdktypes.NewDecWithPrec(15, 1))
+	require.Equal(t, "0.037500000000000000stake,0.000150000000000000uatom", raised.String())
+	require.Equal(t, raised, transaction.GasPrices)
+	require.Equal(t, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 7500), sdktypes.NewInt64Coin("uatom", 30)), transaction.Fee(200000))
+}
+
+func TestIsInsufficientFee(t *testing.T) {
+	require.True(t, tx.IsInsufficientFee(&sdktypes.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrInsufficientFee.ABCICode(),
+		RawLog:    "insufficient fees; got: 1stake required: 5000stake: insufficient fee",
+	}))
+	require.False(t, tx.IsInsufficientFee(&sdktypes.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+	}))
+	require.False(t, tx.IsInsufficientFee(&sdktypes.TxResponse{}))
+}
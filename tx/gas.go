@@ -0,0 +1,156 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+)
+
+// GasEstimator estimates the gas limit of transactions by simulating them.
+// The estimate of a message shape, i.e. the types and the number of the messages of a transaction,
+// is simulated once and cached, so that the gas limit of a run is not simulated for every transaction.
+type GasEstimator struct {
+	adjustment float64
+
+	mu        sync.Mutex
+	estimates map[string]uint64
+}
+
+// NewGasEstimator returns new GasEstimator object.
+// The gas used by a simulation is multiplied by the adjustment to get the gas limit.
+func NewGasEstimator(adjustment float64) *GasEstimator {
+	return &GasEstimator{
+		adjustment: adjustment,
+		estimates:  make(map[string]uint64),
+	}
+}
+
+// Estimate returns the cached gas limit of the message shape, simulating the transaction when it is not cached yet.
+func (e *GasEstimator) Estimate(ctx context.Context, t *Transaction, signer wallet.Signer, msgs ...sdktypes.Msg) (uint64, error) {
+	key := msgShape(msgs)
+
+	e.mu.Lock()
+	gasLimit, ok := e.estimates[key]
+	e.mu.Unlock()
+
+	if ok {
+		return gasLimit, nil
+	}
+
+	gasUsed, err := t.Simulate(ctx, signer, msgs...)
+	if err != nil {
+		return 0, err
+	}
+	gasLimit = uint64(math.Ceil(e.adjustment * float64(gasUsed)))
+
+	e.mu.Lock()
+	e.estimates[key] = gasLimit
+	e.mu.Unlock()
+
+	return gasLimit, nil
+}
+
+// Estimates returns the cached gas limits by message shape.
+func (e *GasEstimator) Estimates() map[string]uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	estimates := make(map[string]uint64, len(e.estimates))
+	for key, gasLimit := range e.estimates {
+		estimates[key] = gasLimit
+	}
+
+	return estimates
+}
+
+// msgShape returns the types of the messages in order, e.g. "*types.MsgSend,*types.MsgSend".
+func msgShape(msgs []sdktypes.Msg) string {
+	types := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		types = append(types, fmt.Sprintf("%T", msg))
+	}
+
+	return strings.Join(types, ",")
+}
+
+// Simulate simulates the transaction of the messages and returns the gas used.
+// The transaction is simulated with the committed sequence of the signer, because the simulation runs against the
+// committed state, and with empty signatures, because signatures are not verified in a simulation.
+// The fee of the simulated transaction is the fee of a single gas, which consumes as much gas as the actual fee.
+func (t *Transaction) Simulate(ctx context.Context, signer wallet.Signer, msgs ...sdktypes.Msg) (uint64, error) {
+	address := sdktypes.AccAddress(signer.PubKey().Address()).String()
+
+	account, err := t.Client.GRPC.GetBaseAccountInfo(ctx, address)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account information: %s", err)
+	}
+
+	txBuilder := t.Client.CliCtx.TxConfig.NewTxBuilder()
+	txBuilder.SetMsgs(msgs...)
+	txBuilder.SetGasLimit(t.GasLimit)
+	txBuilder.SetFeeAmount(t.Fee(1))
+	txBuilder.SetMemo(t.Memo)
+
+	var sigData signing.SignatureData = &signing.SingleSignatureData{
+		SignMode: t.Client.CliCtx.TxConfig.SignModeHandler().DefaultMode(),
+	}
+
+	// the verification gas of a multisig is consumed for the keys that sign it
+	if multisigSigner, ok := signer.(*wallet.MultisigSigner); ok {
+		pubKeys := multisigSigner.PubKey().(*kmultisig.LegacyAminoPubKey).GetPubKeys()
+		mSig := multisig.NewMultisig(len(pubKeys))
+
+		for _, s := range multisigSigner.Signers()[:multisigSigner.Threshold()] {
+			err = multisig.AddSignatureV2(mSig, signing.SignatureV2{
+				PubKey: s.PubKey(),
+				Data: &signing.SingleSignatureData{
+					SignMode: signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON,
+				},
+			}, pubKeys)
+			if err != nil {
+				return 0, fmt.Errorf("failed to add signature to multisignature: %s", err)
+			}
+		}
+		sigData = mSig
+	}
+
+	err = txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey:   signer.PubKey(),
+		Data:     sigData,
+		Sequence: account.GetSequence(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to set signatures: %s", err)
+	}
+
+	protoProvider, ok := txBuilder.(authtx.ProtoTxProvider)
+	if !ok {
+		return 0, fmt.Errorf("cannot simulate transaction of type %T", txBuilder)
+	}
+
+	resp, err := t.Client.GRPC.Simulate(ctx, protoProvider.GetProtoTx())
+	if err != nil {
+		return 0, fmt.Errorf("failed to simulate transaction: %s", err)
+	}
+
+	return resp.GasInfo.GetGasUsed(), nil
+}
+
+// gasLimit returns the estimated gas limit of the messages, or the fixed gas limit when gas is not estimated.
+func (t *Transaction) gasLimit(ctx context.Context, signer wallet.Signer, msgs ...sdktypes.Msg) (uint64, error) {
+	if t.GasEstimator == nil {
+		return t.GasLimit, nil
+	}
+
+	return t.GasEstimator.Estimate(ctx, t, signer, msgs...)
+}
@@ -0,0 +1,140 @@
+package tx_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/test-go/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// simulateServer is a node that simulates every transaction to consume the same gas.
+type simulateServer struct {
+	sdktx.UnimplementedServiceServer
+	authtypes.UnimplementedQueryServer
+
+	gasUsed   uint64
+	sequence  uint64
+	simulated int32
+}
+
+func (s *simulateServer) Simulate(ctx context.Context, req *sdktx.SimulateRequest) (*sdktx.SimulateResponse, error) {
+	atomic.AddInt32(&s.simulated, 1)
+
+	gasUsed := s.gasUsed
+	// the simulation fails when the transaction is not signed with the committed sequence
+	if req.Tx.AuthInfo.SignerInfos[0].Sequence != s.sequence {
+		gasUsed = 0
+	}
+
+	return &sdktx.SimulateResponse{
+		GasInfo: &sdktypes.GasInfo{GasUsed: gasUsed},
+	}, nil
+}
+
+func (s *simulateServer) Account(ctx context.Context, req *authtypes.QueryAccountRequest) (*authtypes.QueryAccountResponse, error) {
+	account, err := codectypes.NewAnyWithValue(&authtypes.BaseAccount{
+		Address:  req.Address,
+		Sequence: s.sequence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authtypes.QueryAccountResponse{Account: account}, nil
+}
+
+func TestGasEstimator(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	server := &simulateServer{gasUsed: 80000, sequence: 3}
+	grpcServer := grpc.NewServer()
+	sdktx.RegisterServiceServer(grpcServer, server)
+	authtypes.RegisterQueryServer(grpcServer, server)
+	go grpcServer.Serve(l) // nolint: errcheck
+	defer grpcServer.Stop()
+
+	simClient, err := client.NewClient(rpcAddress, l.Addr().String())
+	require.NoError(t, err)
+
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 2)
+	require.NoError(t, err)
+	acc := accounts[0]
+
+	msg, err := tx.MsgSend(acc.Address, accounts[1].Address, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 1)))
+	require.NoError(t, err)
+
+	transaction := tx.NewTransaction(simClient, "localnet", 100000000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "")
+	transaction.GasEstimator = tx.NewGasEstimator(1.5)
+
+	// the estimate is simulated once per message shape
+	for seq := uint64(3); seq < 6; seq++ {
+		txByte, err := transaction.Sign(context.Background(), seq, 7, acc.Signer, msg)
+		require.NoError(t, err)
+
+		decoded, err := simClient.CliCtx.TxConfig.TxDecoder()(txByte)
+		require.NoError(t, err)
+		require.Equal(t, uint64(120000), decoded.(sdktypes.FeeTx).GetGas())
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&server.simulated))
+
+	_, err = transaction.Sign(context.Background(), 6, 7, acc.Signer, msg, msg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&server.simulated))
+	require.Len(t, transaction.GasEstimator.Estimates(), 2)
+}
+
+func TestGasEstimatorPadding(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	server := &simulateServer{gasUsed: 80000, sequence: 3}
+	grpcServer := grpc.NewServer()
+	sdktx.RegisterServiceServer(grpcServer, server)
+	authtypes.RegisterQueryServer(grpcServer, server)
+	go grpcServer.Serve(l) // nolint: errcheck
+	defer grpcServer.Stop()
+
+	simClient, err := client.NewClient(rpcAddress, l.Addr().String())
+	require.NoError(t, err)
+
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 2)
+	require.NoError(t, err)
+	acc := accounts[0]
+
+	msg, err := tx.MsgSend(acc.Address, accounts[1].Address, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 1)))
+	require.NoError(t, err)
+
+	transaction := tx.NewTransaction(simClient, "localnet", 100000000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "stress")
+	transaction.GasEstimator = tx.NewGasEstimator(1.5)
+	transaction.TargetSize = 400
+	transaction.TxSizeCostPerByte = 10
+
+	// the padding characters of the memo are charged on top of the simulated gas
+	txByte, err := transaction.Sign(context.Background(), 3, 7, acc.Signer, msg)
+	require.NoError(t, err)
+	require.Equal(t, 400, len(txByte))
+
+	decoded, err := simClient.CliCtx.TxConfig.TxDecoder()(txByte)
+	require.NoError(t, err)
+
+	padding := len(decoded.(sdktypes.TxWithMemo).GetMemo()) - len("stress")
+	require.True(t, padding > 0)
+	require.Equal(t, uint64(120000+padding*10), decoded.(sdktypes.FeeTx).GetGas())
+}
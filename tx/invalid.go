@@ -0,0 +1,172 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// InvalidKind is a category of deliberately broken transactions.
+type InvalidKind string
+
+const (
+	// InvalidSignature is a transaction whose signature is corrupted.
+	InvalidSignature InvalidKind = "bad-signature"
+	// InvalidChainID is a transaction signed for another chain ID.
+	InvalidChainID InvalidKind = "wrong-chain-id"
+	// InvalidStaleSequence is a transaction signed with the sequence before the account sequence.
+	InvalidStaleSequence InvalidKind = "stale-sequence"
+	// InvalidFutureSequence is a transaction signed with a sequence far after the account sequence.
+	InvalidFutureSequence InvalidKind = "future-sequence"
+	// InvalidInsufficientFee is a transaction without fees, rejected by nodes that set minimum gas prices.
+	InvalidInsufficientFee InvalidKind = "insufficient-fee"
+	// InvalidLowGas is a transaction whose gas limit is below the gas consumed before its messages are executed.
+	InvalidLowGas InvalidKind = "low-gas"
+	// InvalidOversizedMemo is a transaction whose memo is longer than the maximum memo characters.
+	InvalidOversizedMemo InvalidKind = "oversized-memo"
+	// InvalidUnknownMsg is a transaction of a message whose type URL is not registered.
+	InvalidUnknownMsg InvalidKind = "unknown-msg"
+	// InvalidTruncated is a transaction whose protobuf bytes are truncated.
+	InvalidTruncated InvalidKind = "truncated"
+)
+
+const (
+	// futureSequenceGap is how far after the account sequence a future sequence is.
+	futureSequenceGap = 100
+
+	// oversizedMemoLength is four times the default max_memo_characters auth parameter.
+	oversizedMemoLength = 1024
+
+	// unknownMsgTypeURL is the type URL of a message that no chain registers.
+	unknownMsgTypeURL = "/tester.v1beta1.MsgUnknown"
+)
+
+// InvalidKinds are every category of broken transactions.
+var InvalidKinds = []InvalidKind{
+	InvalidSignature,
+	InvalidChainID,
+	InvalidStaleSequence,
+	InvalidFutureSequence,
+	InvalidInsufficientFee,
+	InvalidLowGas,
+	InvalidOversizedMemo,
+	InvalidUnknownMsg,
+	InvalidTruncated,
+}
+
+// ParseInvalidKind parses the name of a category of broken transactions.
+func ParseInvalidKind(kind string) (InvalidKind, error) {
+	for _, k := range InvalidKinds {
+		if InvalidKind(kind) == k {
+			return k, nil
+		}
+	}
+
+	names := make([]string, 0, len(InvalidKinds))
+	for _, k := range InvalidKinds {
+		names = append(names, string(k))
+	}
+
+	return "", fmt.Errorf("unknown invalid tx kind %s; must be one of %s", kind, strings.Join(names, ", "))
+}
+
+// SignInvalid signs a transaction of the messages that is broken in the way of the kind.
+// The account sequence is the sequence a valid transaction of the account would be signed with.
+func (t *Transaction) SignInvalid(ctx context.Context, kind InvalidKind, accSeq uint64, accNum uint64, signer wallet.Signer, msgs ...sdktypes.Msg) ([]byte, error) {
+	switch kind {
+	case InvalidSignature:
+		txByte, err := t.Sign(ctx, accSeq, accNum, signer, msgs...)
+		if err != nil {
+			return nil, err
+		}
+
+		return modifyTxRaw(txByte, func(raw *sdktx.TxRaw) error {
+			sig := raw.Signatures[0]
+			sig[len(sig)-1] ^= 0xff
+			return nil
+		})
+
+	case InvalidChainID:
+		return t.with(func(c *Transaction) {
+			c.ChainID = t.ChainID + "-invalid"
+		}).Sign(ctx, accSeq, accNum, signer, msgs...)
+
+	case InvalidStaleSequence:
+		if accSeq == 0 {
+			return nil, fmt.Errorf("account has no sequence before 0 to sign a stale transaction with")
+		}
+
+		return t.Sign(ctx, accSeq-1, accNum, signer, msgs...)
+
+	case InvalidFutureSequence:
+		return t.Sign(ctx, accSeq+futureSequenceGap, accNum, signer, msgs...)
+
+	case InvalidInsufficientFee:
+		return t.with(func(c *Transaction) {
+			c.Fees = sdktypes.NewCoins()
+			c.GasPrices = nil
+		}).Sign(ctx, accSeq, accNum, signer, msgs...)
+
+	case InvalidLowGas:
+		return t.with(func(c *Transaction) {
+			c.GasLimit = 1
+			c.GasEstimator = nil
+		}).Sign(ctx, accSeq, accNum, signer, msgs...)
+
+	case InvalidOversizedMemo:
+		return t.with(func(c *Transaction) {
+			c.Memo = strings.Repeat("x", oversizedMemoLength)
+		}).Sign(ctx, accSeq, accNum, signer, msgs...)
+
+	case InvalidUnknownMsg:
+		txByte, err := t.Sign(ctx, accSeq, accNum, signer, msgs...)
+		if err != nil {
+			return nil, err
+		}
+
+		return modifyTxRaw(txByte, func(raw *sdktx.TxRaw) error {
+			var body sdktx.TxBody
+			err := body.Unmarshal(raw.BodyBytes)
+			if err != nil {
+				return err
+			}
+
+			body.Messages[0].TypeUrl = unknownMsgTypeURL
+
+			raw.BodyBytes, err = body.Marshal()
+			return err
+		})
+
+	case InvalidTruncated:
+		txByte, err := t.Sign(ctx, accSeq, accNum, signer, msgs...)
+		if err != nil {
+			return nil, err
+		}
+
+		return txByte[:len(txByte)/2], nil
+
+	default:
+		return nil, fmt.Errorf("unknown invalid tx kind %s", kind)
+	}
+}
+
+// modifyTxRaw decodes the raw transaction of the bytes, modifies it with fn and encodes it again.
+func modifyTxRaw(txByte []byte, fn func(raw *sdktx.TxRaw) error) ([]byte, error) {
+	var raw sdktx.TxRaw
+	err := raw.Unmarshal(txByte)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode raw tx: %s", err)
+	}
+
+	err = fn(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify raw tx: %s", err)
+	}
+
+	return raw.Marshal()
+}
@@ -0,0 +1,108 @@
+package tx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+func TestSignInvalid(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 1)
+	require.NoError(t, err)
+	acc := accounts[0]
+
+	msg, err := tx.MsgSend(acc.Address, acc.Address, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 1)))
+	require.NoError(t, err)
+
+	transaction := tx.NewTransaction(c, "localnet", 200000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "")
+	signerData := authsigning.SignerData{ChainID: "localnet", AccountNumber: 7, Sequence: 5}
+
+	decode := func(kind tx.InvalidKind) (authsigning.Tx, error) {
+		txByte, err := transaction.SignInvalid(context.Background(), kind, 5, 7, acc.Signer, msg)
+		require.NoError(t, err)
+
+		decoded, err := c.CliCtx.TxConfig.TxDecoder()(txByte)
+		if err != nil {
+			return nil, err
+		}
+
+		return decoded.(authsigning.Tx), nil
+	}
+
+	verify := func(decoded authsigning.Tx) error {
+		sigs, err := decoded.GetSignaturesV2()
+		require.NoError(t, err)
+
+		return authsigning.VerifySignature(sigs[0].PubKey, signerData, sigs[0].Data, c.CliCtx.TxConfig.SignModeHandler(), decoded)
+	}
+
+	for _, kind := range []tx.InvalidKind{tx.InvalidSignature, tx.InvalidChainID} {
+		decoded, err := decode(kind)
+		require.NoError(t, err)
+		require.Error(t, verify(decoded), kind)
+	}
+
+	for kind, expected := range map[tx.InvalidKind]uint64{tx.InvalidStaleSequence: 4, tx.InvalidFutureSequence: 105} {
+		decoded, err := decode(kind)
+		require.NoError(t, err)
+
+		sigs, err := decoded.GetSignaturesV2()
+		require.NoError(t, err)
+		require.Equal(t, expected, sigs[0].Sequence)
+	}
+
+	decoded, err := decode(tx.InvalidInsufficientFee)
+	require.NoError(t, err)
+	require.True(t, decoded.GetFee().IsZero())
+	require.NoError(t, verify(decoded))
+
+	decoded, err = decode(tx.InvalidLowGas)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), decoded.GetGas())
+	require.NoError(t, verify(decoded))
+
+	decoded, err = decode(tx.InvalidOversizedMemo)
+	require.NoError(t, err)
+	require.True(t, len(decoded.GetMemo()) > 256)
+
+	// the transaction is valid but for its fee, gas or memo
+	require.Equal(t, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), transaction.Fees)
+	require.Empty(t, transaction.Memo)
+
+	_, err = decode(tx.InvalidUnknownMsg)
+	require.Error(t, err)
+
+	txByte, err := transaction.SignInvalid(context.Background(), tx.InvalidUnknownMsg, 5, 7, acc.Signer, msg)
+	require.NoError(t, err)
+
+	var raw sdktx.TxRaw
+	require.NoError(t, raw.Unmarshal(txByte))
+	var body sdktx.TxBody
+	require.NoError(t, body.Unmarshal(raw.BodyBytes))
+	require.Equal(t, "/tester.v1beta1.MsgUnknown", body.Messages[0].TypeUrl)
+
+	_, err = decode(tx.InvalidTruncated)
+	require.Error(t, err)
+
+	_, err = transaction.SignInvalid(context.Background(), tx.InvalidStaleSequence, 0, 7, acc.Signer, msg)
+	require.Error(t, err)
+
+	for _, kind := range tx.InvalidKinds {
+		parsed, err := tx.ParseInvalidKind(string(kind))
+		require.NoError(t, err)
+		require.Equal(t, kind, parsed)
+	}
+
+	_, err = tx.ParseInvalidKind("valid")
+	require.Error(t, err)
+}
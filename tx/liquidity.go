@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sync"
 
 	"github.com/b-harvest/cosmos-module-stress-test/client"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
 
 	liquiditytypes "github.com/tendermint/liquidity/x/liquidity/types"
 
-	sdkclienttx "github.com/cosmos/cosmos-sdk/client/tx"
-	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
@@ -23,6 +26,26 @@ type Transaction struct {
 	GasLimit uint64         `json:"gas_limit"`
 	Fees     sdktypes.Coins `json:"fees"`
 	Memo     string         `json:"memo"`
+	SignMode SignMode       `json:"sign_mode"`
+
+	// GasPrices computes the fees of every transaction from its gas limit instead of the fixed Fees when it is set.
+	GasPrices sdktypes.DecCoins `json:"gas_prices"`
+
+	// GasEstimator estimates the gas limit of every transaction instead of the fixed GasLimit when it is set.
+	GasEstimator *GasEstimator `json:"-"`
+
+	// SignWorkers is the number of goroutines SignAll signs transactions with, or the number of CPUs when it is 0.
+	SignWorkers int `json:"sign_workers"`
+
+	// TargetSize is the size in bytes every transaction is padded to with extra messages and memo characters
+	// when it is positive. The memo is padded up to MaxMemoCharacters, and the estimated gas limit is raised by
+	// TxSizeCostPerByte for every padding character, which the simulation of the unpadded memo does not consume.
+	TargetSize        int    `json:"target_size"`
+	MaxMemoCharacters uint64 `json:"max_memo_characters"`
+	TxSizeCostPerByte uint64 `json:"tx_size_cost_per_byte"`
+
+	mu             *sync.Mutex // not shared by the copies of the transaction
+	signModeCounts map[signing.SignMode]uint64
 }
 
 // NewTransaction returns new Transaction object.
@@ -33,9 +56,28 @@ func NewTransaction(client *client.Client, chainID string, gasLimit uint64, fees
 		GasLimit: gasLimit,
 		Fees:     fees,
 		Memo:     memo,
+		mu:       &sync.Mutex{},
 	}
 }
 
+// with returns a copy of the transaction modified by fn, sharing its client and gas estimator.
+// Transactions signed by the copy are not counted by the transaction.
+func (t *Transaction) with(fn func(*Transaction)) *Transaction {
+	t.mu.Lock()
+	c := *t
+	t.mu.Unlock()
+
+	c.mu = &sync.Mutex{}
+	c.signModeCounts = make(map[signing.SignMode]uint64, len(t.signModeCounts))
+	for mode, count := range t.signModeCounts {
+		c.signModeCounts[mode] = count
+	}
+
+	fn(&c)
+
+	return &c
+}
+
 // MsgCreatePool creates create pool message and returns MsgCreatePool transaction message.
 func MsgCreatePool(poolCreator string, poolTypeId uint32, depositCoins sdktypes.Coins) (sdktypes.Msg, error) {
 	accAddr, err := sdktypes.AccAddressFromBech32(poolCreator)
@@ -109,17 +151,11 @@ func (t *Transaction) CreateSwapBot(ctx context.Context, poolCreator string,
 		return []sdktypes.Msg{}, err
 	}
 
-	reserveCoins := sdktypes.NewCoins()
-	for _, denom := range pool.ReserveCoinDenoms {
-		coin, err := t.Client.GRPC.GetBalance(ctx, pool.GetReserveAccount().String(), denom)
-		if err != nil {
-			return []sdktypes.Msg{}, err
-		}
-		reserveCoins = reserveCoins.Add(*coin)
+	orderPrice, err := t.poolPrice(ctx, pool)
+	if err != nil {
+		return []sdktypes.Msg{}, err
 	}
 
-	orderPrice := reserveCoins.AmountOf(pool.ReserveCoinDenoms[0]).ToDec().Quo(reserveCoins.AmountOf(pool.ReserveCoinDenoms[1]).ToDec())
-
 	var msgs []sdktypes.Msg
 
 	// randomize order price
@@ -143,18 +179,56 @@ func (t *Transaction) CreateSwapBot(ctx context.Context, poolCreator string,
 	return msgs, nil
 }
 
-// Sign signs message(s) with the account's private key and braodacasts the message(s).
-func (t *Transaction) Sign(ctx context.Context, accSeq uint64, accNum uint64, privKey *secp256k1.PrivKey, msgs ...sdktypes.Msg) ([]byte, error) {
+// poolPrice returns the price of the pool, which is the reserve of the first reserve coin over the reserve of the second.
+func (t *Transaction) poolPrice(ctx context.Context, pool liquiditytypes.Pool) (sdktypes.Dec, error) {
+	reserveCoins := sdktypes.NewCoins()
+	for _, denom := range pool.ReserveCoinDenoms {
+		coin, err := t.Client.GRPC.GetBalance(ctx, pool.GetReserveAccount().String(), denom)
+		if err != nil {
+			return sdktypes.Dec{}, err
+		}
+		reserveCoins = reserveCoins.Add(*coin)
+	}
+
+	return reserveCoins.AmountOf(pool.ReserveCoinDenoms[0]).ToDec().Quo(reserveCoins.AmountOf(pool.ReserveCoinDenoms[1]).ToDec()), nil
+}
+
+// Sign signs message(s) with the account's signer and returns the encoded transaction bytes.
+// The signer can be either a raw private key or a key stored in a keyring.
+// The transaction is padded to the target size when it is set.
+func (t *Transaction) Sign(ctx context.Context, accSeq uint64, accNum uint64, signer wallet.Signer, msgs ...sdktypes.Msg) ([]byte, error) {
+	if t.TargetSize > 0 {
+		return t.signPadded(ctx, accSeq, accNum, signer, msgs...)
+	}
+
+	return t.sign(ctx, accSeq, accNum, signer, t.Memo, msgs...)
+}
+
+// sign signs the transaction of the messages and the memo.
+func (t *Transaction) sign(ctx context.Context, accSeq uint64, accNum uint64, signer wallet.Signer, memo string, msgs ...sdktypes.Msg) ([]byte, error) {
+	gasLimit, err := t.gasLimit(ctx, signer, msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.GasEstimator != nil && len(memo) > len(t.Memo) {
+		gasLimit += uint64(len(memo)-len(t.Memo)) * t.TxSizeCostPerByte
+	}
+
 	txBuilder := t.Client.CliCtx.TxConfig.NewTxBuilder()
 	txBuilder.SetMsgs(msgs...)
-	txBuilder.SetGasLimit(t.GasLimit)
-	txBuilder.SetFeeAmount(t.Fees)
-	txBuilder.SetMemo(t.Memo)
+	txBuilder.SetGasLimit(gasLimit)
+	txBuilder.SetFeeAmount(t.Fee(gasLimit))
+	txBuilder.SetMemo(memo)
+
+	if multisigSigner, ok := signer.(*wallet.MultisigSigner); ok {
+		return t.signMultisig(txBuilder, accSeq, accNum, multisigSigner)
+	}
 
-	signMode := t.Client.CliCtx.TxConfig.SignModeHandler().DefaultMode()
+	signMode := t.signMode()
 
 	sigV2 := signing.SignatureV2{
-		PubKey: privKey.PubKey(),
+		PubKey: signer.PubKey(),
 		Data: &signing.SingleSignatureData{
 			SignMode:  signMode,
 			Signature: nil,
@@ -162,7 +236,7 @@ func (t *Transaction) Sign(ctx context.Context, accSeq uint64, accNum uint64, pr
 		Sequence: accSeq,
 	}
 
-	err := txBuilder.SetSignatures(sigV2)
+	err = txBuilder.SetSignatures(sigV2)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set signatures: %s", err)
 	}
@@ -173,9 +247,23 @@ func (t *Transaction) Sign(ctx context.Context, accSeq uint64, accNum uint64, pr
 		Sequence:      accSeq,
 	}
 
-	sigV2, err = sdkclienttx.SignWithPrivKey(signMode, signerData, txBuilder, privKey, t.Client.CliCtx.TxConfig, accSeq)
+	signBytes, err := t.Client.CliCtx.TxConfig.SignModeHandler().GetSignBytes(signMode, signerData, txBuilder.GetTx())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sign bytes: %s", err)
+	}
+
+	signature, err := signer.Sign(signBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign with private key: %s", err)
+		return nil, fmt.Errorf("failed to sign: %s", err)
+	}
+
+	sigV2 = signing.SignatureV2{
+		PubKey: signer.PubKey(),
+		Data: &signing.SingleSignatureData{
+			SignMode:  signMode,
+			Signature: signature,
+		},
+		Sequence: accSeq,
 	}
 
 	err = txBuilder.SetSignatures(sigV2)
@@ -188,5 +276,68 @@ func (t *Transaction) Sign(ctx context.Context, accSeq uint64, accNum uint64, pr
 		return nil, fmt.Errorf("failed to encode tx and get raw tx data: %s", err)
 	}
 
+	t.countSignMode(signMode)
+
+	return txByte, nil
+}
+
+// signMultisig signs the transaction with the first threshold keys of the multisig and sets their multisignature.
+// The keys sign in the legacy amino JSON sign mode whatever the sign mode of the transaction is, because it is
+// the sign mode supported for multisig accounts.
+func (t *Transaction) signMultisig(txBuilder sdkclient.TxBuilder, accSeq uint64, accNum uint64, signer *wallet.MultisigSigner) ([]byte, error) {
+	signMode := signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+
+	signerData := authsigning.SignerData{
+		ChainID:       t.ChainID,
+		AccountNumber: accNum,
+		Sequence:      accSeq,
+	}
+
+	signBytes, err := t.Client.CliCtx.TxConfig.SignModeHandler().GetSignBytes(signMode, signerData, txBuilder.GetTx())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sign bytes: %s", err)
+	}
+
+	pubKey := signer.PubKey().(*kmultisig.LegacyAminoPubKey)
+	pubKeys := pubKey.GetPubKeys()
+	mSig := multisig.NewMultisig(len(pubKeys))
+
+	for _, s := range signer.Signers()[:signer.Threshold()] {
+		signature, err := s.Sign(signBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign: %s", err)
+		}
+
+		sigV2 := signing.SignatureV2{
+			PubKey: s.PubKey(),
+			Data: &signing.SingleSignatureData{
+				SignMode:  signMode,
+				Signature: signature,
+			},
+			Sequence: accSeq,
+		}
+
+		err = multisig.AddSignatureV2(mSig, sigV2, pubKeys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add signature to multisignature: %s", err)
+		}
+	}
+
+	err = txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     mSig,
+		Sequence: accSeq,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set signatures: %s", err)
+	}
+
+	txByte, err := t.Client.CliCtx.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tx and get raw tx data: %s", err)
+	}
+
+	t.countSignMode(signMode)
+
 	return txByte, nil
 }
@@ -0,0 +1,102 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MixedMsgKind is a kind of liquidity message in a mixed transaction.
+type MixedMsgKind string
+
+const (
+	// MixedMsgSwap is a MsgSwapWithinBatch offering one reserve coin of the pool for the other.
+	MixedMsgSwap MixedMsgKind = "swap"
+	// MixedMsgDeposit is a MsgDepositWithinBatch depositing both reserve coins of the pool.
+	MixedMsgDeposit MixedMsgKind = "deposit"
+	// MixedMsgWithdraw is a MsgWithdrawWithinBatch withdrawing the pool coin of the pool.
+	MixedMsgWithdraw MixedMsgKind = "withdraw"
+)
+
+// MixedMsgKinds are every kind of liquidity message in a mixed transaction.
+var MixedMsgKinds = []MixedMsgKind{
+	MixedMsgSwap,
+	MixedMsgDeposit,
+	MixedMsgWithdraw,
+}
+
+// MixedPool is the state of a pool that the messages of a mixed transaction are made from.
+type MixedPool struct {
+	Id                uint64
+	ReserveCoinDenoms []string
+	PoolCoinDenom     string
+	Price             sdktypes.Dec
+}
+
+// GetMixedPools queries the pools and their current prices.
+func (t *Transaction) GetMixedPools(ctx context.Context, poolIds []uint64) ([]MixedPool, error) {
+	pools := make([]MixedPool, 0, len(poolIds))
+	for _, poolId := range poolIds {
+		pool, err := t.Client.GRPC.GetPool(ctx, poolId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pool %d: %s", poolId, err)
+		}
+
+		price, err := t.poolPrice(ctx, pool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price of pool %d: %s", poolId, err)
+		}
+
+		pools = append(pools, MixedPool{
+			Id:                pool.Id,
+			ReserveCoinDenoms: pool.ReserveCoinDenoms,
+			PoolCoinDenom:     pool.PoolCoinDenom,
+			Price:             price,
+		})
+	}
+
+	return pools, nil
+}
+
+// MixedMsgs creates one message of every kind in order. The messages go to the pools in turn and each of them
+// moves the amount of a coin: a swap offers the amount of a reserve coin at the pool price, alternating between
+// the two reserve coins on every pass over the pools, a deposit deposits the amount of both reserve coins and
+// a withdrawal withdraws the amount of the pool coin.
+func MixedMsgs(address string, pools []MixedPool, kinds []MixedMsgKind, amount sdktypes.Int) ([]sdktypes.Msg, error) {
+	if len(pools) == 0 {
+		return nil, fmt.Errorf("at least one pool must be given")
+	}
+
+	msgs := make([]sdktypes.Msg, 0, len(kinds))
+	for i, kind := range kinds {
+		pool := pools[i%len(pools)]
+
+		var msg sdktypes.Msg
+		var err error
+		switch kind {
+		case MixedMsgSwap:
+			offer := (i / len(pools)) % 2
+			offerCoin := sdktypes.NewCoin(pool.ReserveCoinDenoms[offer], amount)
+			demandCoinDenom := pool.ReserveCoinDenoms[1-offer]
+			msg, err = MsgSwap(address, pool.Id, uint32(1), offerCoin, demandCoinDenom, pool.Price, sdktypes.NewDecWithPrec(3, 3))
+		case MixedMsgDeposit:
+			depositCoins := sdktypes.NewCoins(
+				sdktypes.NewCoin(pool.ReserveCoinDenoms[0], amount),
+				sdktypes.NewCoin(pool.ReserveCoinDenoms[1], amount),
+			)
+			msg, err = MsgDeposit(address, pool.Id, depositCoins)
+		case MixedMsgWithdraw:
+			msg, err = MsgWithdraw(address, pool.Id, sdktypes.NewCoin(pool.PoolCoinDenom, amount))
+		default:
+			return nil, fmt.Errorf("unknown msg kind %s", kind)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s msg for pool %d: %s", kind, pool.Id, err)
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
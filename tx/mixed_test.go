@@ -0,0 +1,58 @@
+package tx_test
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	liquiditytypes "github.com/tendermint/liquidity/x/liquidity/types"
+)
+
+func TestMixedMsgs(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 1)
+	require.NoError(t, err)
+	address := accounts[0].Address
+
+	pools := []tx.MixedPool{
+		{Id: 1, ReserveCoinDenoms: []string{"uatom", "stake"}, PoolCoinDenom: "pool1", Price: sdktypes.NewDec(2)},
+		{Id: 2, ReserveCoinDenoms: []string{"stake", "uiris"}, PoolCoinDenom: "pool2", Price: sdktypes.NewDecWithPrec(5, 1)},
+	}
+	kinds := []tx.MixedMsgKind{tx.MixedMsgSwap, tx.MixedMsgDeposit, tx.MixedMsgSwap, tx.MixedMsgWithdraw, tx.MixedMsgSwap}
+
+	msgs, err := tx.MixedMsgs(address, pools, kinds, sdktypes.NewInt(1000))
+	require.NoError(t, err)
+	require.Len(t, msgs, len(kinds))
+
+	swap := msgs[0].(*liquiditytypes.MsgSwapWithinBatch)
+	require.Equal(t, uint64(1), swap.PoolId)
+	require.Equal(t, "uatom", swap.OfferCoin.Denom)
+	require.Equal(t, "stake", swap.DemandCoinDenom)
+	require.Equal(t, sdktypes.NewDec(2), swap.OrderPrice)
+
+	deposit := msgs[1].(*liquiditytypes.MsgDepositWithinBatch)
+	require.Equal(t, uint64(2), deposit.PoolId)
+	require.Equal(t, "1000stake,1000uiris", deposit.DepositCoins.String())
+
+	// the offer coin alternates on the second pass over the pools
+	swap = msgs[2].(*liquiditytypes.MsgSwapWithinBatch)
+	require.Equal(t, uint64(1), swap.PoolId)
+	require.Equal(t, "stake", swap.OfferCoin.Denom)
+	require.Equal(t, "uatom", swap.DemandCoinDenom)
+
+	withdraw := msgs[3].(*liquiditytypes.MsgWithdrawWithinBatch)
+	require.Equal(t, uint64(2), withdraw.PoolId)
+	require.Equal(t, "1000pool2", withdraw.PoolCoin.String())
+
+	swap = msgs[4].(*liquiditytypes.MsgSwapWithinBatch)
+	require.Equal(t, uint64(1), swap.PoolId)
+	require.Equal(t, "uatom", swap.OfferCoin.Denom)
+
+	_, err = tx.MixedMsgs(address, nil, kinds, sdktypes.NewInt(1000))
+	require.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package tx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+func TestSignMultisig(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 3)
+	require.NoError(t, err)
+
+	multisigs, err := wallet.NewMultisigAccounts(accounts, 2, 3)
+	require.NoError(t, err)
+	acc := multisigs[0]
+
+	msg, err := tx.MsgSend(acc.Address, accounts[0].Address, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 1)))
+	require.NoError(t, err)
+
+	transaction := tx.NewTransaction(c, "localnet", 200000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "")
+
+	txByte, err := transaction.Sign(context.Background(), 5, 7, acc.Signer, msg)
+	require.NoError(t, err)
+
+	decoded, err := c.CliCtx.TxConfig.TxDecoder()(txByte)
+	require.NoError(t, err)
+
+	sigTx := decoded.(authsigning.SigVerifiableTx)
+	sigs, err := sigTx.GetSignaturesV2()
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+	require.Equal(t, uint64(5), sigs[0].Sequence)
+	require.True(t, acc.Signer.PubKey().Equals(sigs[0].PubKey))
+
+	multiSigData, ok := sigs[0].Data.(*signing.MultiSignatureData)
+	require.True(t, ok)
+	require.Len(t, multiSigData.Signatures, 2)
+
+	signerData := authsigning.SignerData{ChainID: "localnet", AccountNumber: 7, Sequence: 5}
+	err = authsigning.VerifySignature(sigs[0].PubKey, signerData, sigs[0].Data, c.CliCtx.TxConfig.SignModeHandler(), sigTx)
+	require.NoError(t, err)
+
+	// the multisignature does not verify for another account number
+	signerData.AccountNumber = 8
+	err = authsigning.VerifySignature(sigs[0].PubKey, signerData, sigs[0].Data, c.CliCtx.TxConfig.SignModeHandler(), sigTx)
+	require.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// DefaultMaxMemoCharacters is the default max_memo_characters auth parameter.
+const DefaultMaxMemoCharacters = 256
+
+// signPadded signs the transaction of the messages padded to the target size.
+// The messages are repeated in turn until the rest of the target size fits in the memo,
+// and the memo is then padded with as many characters as the rest of the target size.
+// The transaction is signed as it is when it is already as large as the target size.
+func (t *Transaction) signPadded(ctx context.Context, accSeq uint64, accNum uint64, signer wallet.Signer, msgs ...sdktypes.Msg) ([]byte, error) {
+	// the transactions signed to measure the padding are not counted
+	trial := t.with(func(*Transaction) {})
+
+	maxMemo := int(t.MaxMemoCharacters)
+	if maxMemo == 0 {
+		maxMemo = DefaultMaxMemoCharacters
+	}
+	// a memo already as long as the limit is not padded
+	free := maxMemo - len(t.Memo)
+	if free < 0 {
+		free = 0
+	}
+
+	txByte, err := trial.sign(ctx, accSeq, accNum, signer, t.Memo, msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(txByte) >= t.TargetSize || len(msgs) == 0 {
+		return t.sign(ctx, accSeq, accNum, signer, t.Memo, msgs...)
+	}
+
+	padded := msgs
+	if rest := t.TargetSize - len(txByte); rest > free {
+		extra, err := trial.sign(ctx, accSeq, accNum, signer, t.Memo, paddingMsgs(msgs, 1)...)
+		if err != nil {
+			return nil, err
+		}
+		msgSize := len(extra) - len(txByte)
+
+		// as few messages are added as leave no more of the target size than the memo can pad
+		n := (rest - free + msgSize - 1) / msgSize
+		for ; n > 0; n-- {
+			padded = paddingMsgs(msgs, n)
+
+			txByte, err = trial.sign(ctx, accSeq, accNum, signer, t.Memo, padded...)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(txByte) <= t.TargetSize {
+				break
+			}
+		}
+		if n == 0 {
+			padded = msgs
+			txByte, err = trial.sign(ctx, accSeq, accNum, signer, t.Memo, padded...)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	padding, err := memoPadding(txByte, len(t.Memo), t.TargetSize, free)
+	if err != nil {
+		return nil, err
+	}
+
+	// the gas of the padding can lengthen the encoded gas limit and fee, which the padding then makes up for
+	for padding > 0 {
+		txByte, err = trial.sign(ctx, accSeq, accNum, signer, t.Memo+strings.Repeat("x", padding), padded...)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(txByte) <= t.TargetSize {
+			break
+		}
+		padding -= len(txByte) - t.TargetSize
+		if padding < 0 {
+			padding = 0
+		}
+	}
+
+	return t.sign(ctx, accSeq, accNum, signer, t.Memo+strings.Repeat("x", padding), padded...)
+}
+
+// memoPadding returns the number of characters, up to free, the memo of the given length of the encoded transaction
+// is padded with to make the transaction as large as the target size without exceeding it.
+// The size of the padded transaction is computed from the encoded one: the memo field of the body grows with the
+// characters and their length prefix, and the body field of the transaction grows with its length prefix.
+func memoPadding(txBytes []byte, memoLen int, targetSize int, free int) (int, error) {
+	var raw sdktx.TxRaw
+	if err := raw.Unmarshal(txBytes); err != nil {
+		return 0, fmt.Errorf("failed to decode tx: %s", err)
+	}
+	bodyLen := len(raw.BodyBytes)
+
+	size := func(padding int) int {
+		memoDelta := fieldSize(memoLen+padding) - fieldSize(memoLen)
+		return len(txBytes) + fieldSize(bodyLen+memoDelta) - fieldSize(bodyLen)
+	}
+
+	padding := targetSize - len(txBytes)
+	if padding > free {
+		padding = free
+	}
+	for padding > 0 && size(padding) > targetSize {
+		padding -= size(padding) - targetSize
+	}
+	if padding < 0 {
+		padding = 0
+	}
+
+	return padding, nil
+}
+
+// fieldSize returns the encoded size of a length-delimited protobuf field of the length with a single byte tag,
+// which is omitted when it is empty.
+func fieldSize(length int) int {
+	if length == 0 {
+		return 0
+	}
+
+	size := 2
+	for n := length; n >= 0x80; n >>= 7 {
+		size++
+	}
+
+	return size + length
+}
+
+// paddingMsgs returns the messages followed by n of the messages in turn.
+func paddingMsgs(msgs []sdktypes.Msg, n int) []sdktypes.Msg {
+	padded := make([]sdktypes.Msg, 0, len(msgs)+n)
+	padded = append(padded, msgs...)
+	for i := 0; i < n; i++ {
+		padded = append(padded, msgs[i%len(msgs)])
+	}
+
+	return padded
+}
@@ -0,0 +1,72 @@
+package tx_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+func TestSignPadded(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 1)
+	require.NoError(t, err)
+	acc := accounts[0]
+
+	msg, err := tx.MsgSwap(acc.Address, 1, 1, sdktypes.NewInt64Coin("uatom", 1000), "stake", sdktypes.NewDecWithPrec(15, 1), sdktypes.NewDecWithPrec(3, 3))
+	require.NoError(t, err)
+
+	transaction := tx.NewTransaction(c, "localnet", 200000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "stress")
+
+	unpadded, err := transaction.Sign(context.Background(), 5, 7, acc.Signer, msg)
+	require.NoError(t, err)
+
+	for _, targetSize := range []int{len(unpadded) + 1, len(unpadded) + 200, 2000, 20000} {
+		transaction.TargetSize = targetSize
+
+		txByte, err := transaction.Sign(context.Background(), 5, 7, acc.Signer, msg)
+		require.NoError(t, err)
+		require.Equal(t, targetSize, len(txByte))
+
+		decoded, err := c.CliCtx.TxConfig.TxDecoder()(txByte)
+		require.NoError(t, err)
+
+		sigTx := decoded.(authsigning.Tx)
+		require.True(t, len(sigTx.GetMemo()) <= tx.DefaultMaxMemoCharacters)
+		require.Equal(t, "stress", sigTx.GetMemo()[:6])
+
+		sigs, err := sigTx.GetSignaturesV2()
+		require.NoError(t, err)
+
+		signerData := authsigning.SignerData{ChainID: "localnet", AccountNumber: 7, Sequence: 5}
+		err = authsigning.VerifySignature(sigs[0].PubKey, signerData, sigs[0].Data, c.CliCtx.TxConfig.SignModeHandler(), sigTx)
+		require.NoError(t, err)
+	}
+
+	// a transaction larger than the target size is not padded
+	transaction.TargetSize = len(unpadded) - 1
+
+	txByte, err := transaction.Sign(context.Background(), 5, 7, acc.Signer, msg)
+	require.NoError(t, err)
+	require.Equal(t, unpadded, txByte)
+
+	// a memo longer than the max memo characters is not padded
+	transaction.Memo = strings.Repeat("m", tx.DefaultMaxMemoCharacters+1)
+	transaction.TargetSize = 2000
+
+	txByte, err = transaction.Sign(context.Background(), 5, 7, acc.Signer, msg)
+	require.NoError(t, err)
+	require.True(t, len(txByte) <= 2000)
+
+	// only the signed transactions are counted, not the ones measuring the padding
+	require.Equal(t, uint64(7), transaction.SignModeCounts()[signing.SignMode_SIGN_MODE_DIRECT])
+}
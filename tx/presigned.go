@@ -0,0 +1,82 @@
+package tx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// PresignedTx is a signed transaction with the account and the sequence it is signed with.
+// The transaction bytes are encoded in base64 in a JSON line of a presigned tx file.
+type PresignedTx struct {
+	Address       string   `json:"address"`
+	AccountNumber uint64   `json:"account_number"`
+	Sequence      uint64   `json:"sequence"`
+	MsgTypes      []string `json:"msg_types"`
+	TxBytes       []byte   `json:"tx_bytes"`
+}
+
+// MsgTypeURLs returns the type URLs of the messages, e.g. "/cosmos.bank.v1beta1.MsgSend".
+func MsgTypeURLs(msgs []sdktypes.Msg) ([]string, error) {
+	typeURLs := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		any, err := codectypes.NewAnyWithValue(msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack msg: %s", err)
+		}
+		typeURLs = append(typeURLs, any.TypeUrl)
+	}
+
+	return typeURLs, nil
+}
+
+// WritePresignedTxFile writes the transactions to a presigned tx file, one JSON line per transaction.
+func WritePresignedTxFile(path string, txs []PresignedTx) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create presigned tx file: %s", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, tx := range txs {
+		err = enc.Encode(tx)
+		if err != nil {
+			return fmt.Errorf("failed to write presigned tx: %s", err)
+		}
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write presigned tx file: %s", err)
+	}
+
+	return f.Close()
+}
+
+// ReadPresignedTxFile reads every transaction of a presigned tx file in order.
+func ReadPresignedTxFile(path string) ([]PresignedTx, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open presigned tx file: %s", err)
+	}
+	defer f.Close()
+
+	var txs []PresignedTx
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var tx PresignedTx
+		err = dec.Decode(&tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read presigned tx %d: %s", len(txs)+1, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
@@ -0,0 +1,40 @@
+package tx_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestPresignedTxFile(t *testing.T) {
+	sender := "cosmos1zaavvzxez0elundtn32qnk9lkm8kmcszzsv80v"
+
+	msg, err := tx.MsgSend(sender, sender, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 1)))
+	require.NoError(t, err)
+
+	msgTypes, err := tx.MsgTypeURLs([]sdktypes.Msg{msg, msg})
+	require.NoError(t, err)
+	require.Equal(t, []string{"/cosmos.bank.v1beta1.MsgSend", "/cosmos.bank.v1beta1.MsgSend"}, msgTypes)
+
+	txs := []tx.PresignedTx{
+		{Address: sender, AccountNumber: 7, Sequence: 5, MsgTypes: msgTypes, TxBytes: []byte{0x0a, 0x01, 0xff}},
+		{Address: sender, AccountNumber: 7, Sequence: 6, MsgTypes: msgTypes, TxBytes: []byte{0x0a, 0x02, 0x00}},
+	}
+
+	path := filepath.Join(t.TempDir(), "presigned.jsonl")
+
+	err = tx.WritePresignedTxFile(path, txs)
+	require.NoError(t, err)
+
+	read, err := tx.ReadPresignedTxFile(path)
+	require.NoError(t, err)
+	require.Equal(t, txs, read)
+
+	_, err = tx.ReadPresignedTxFile(filepath.Join(t.TempDir(), "missing.jsonl"))
+	require.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package tx
+
+import (
+	"fmt"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// Rejection is the way a node handled a duplicate of a transaction.
+type Rejection string
+
+const (
+	// RejectionNone is a duplicate that passed CheckTx, e.g. on a node that has not seen the original yet.
+	RejectionNone Rejection = "accepted"
+	// RejectionMempoolCache is a duplicate rejected by the mempool cache of the node before CheckTx.
+	RejectionMempoolCache Rejection = "mempool-cache"
+	// RejectionSequence is a duplicate rejected by the sequence check of the ante handler.
+	RejectionSequence Rejection = "sequence"
+	// RejectionOther is a duplicate rejected for another reason, e.g. a full mempool.
+	RejectionOther Rejection = "other"
+)
+
+// ClassifyRejection returns how the node handled the duplicate of the broadcast response.
+func ClassifyRejection(resp *sdktypes.TxResponse) Rejection {
+	if resp.Code == 0 {
+		return RejectionNone
+	}
+
+	if resp.Codespace != sdkerrors.RootCodespace {
+		return RejectionOther
+	}
+
+	switch resp.Code {
+	case sdkerrors.ErrTxInMempoolCache.ABCICode():
+		return RejectionMempoolCache
+	case sdkerrors.ErrWrongSequence.ABCICode():
+		return RejectionSequence
+	default:
+		return RejectionOther
+	}
+}
+
+// TxHash returns the hash of the transaction bytes as Tendermint reports it.
+func TxHash(txBytes []byte) string {
+	return fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash())
+}
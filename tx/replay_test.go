@@ -0,0 +1,36 @@
+package tx_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestClassifyRejection(t *testing.T) {
+	require.Equal(t, tx.RejectionNone, tx.ClassifyRejection(&sdktypes.TxResponse{}))
+	require.Equal(t, tx.RejectionMempoolCache, tx.ClassifyRejection(&sdktypes.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrTxInMempoolCache.ABCICode(),
+	}))
+	require.Equal(t, tx.RejectionSequence, tx.ClassifyRejection(wrongSequenceResponse("6", "5")))
+	require.Equal(t, tx.RejectionOther, tx.ClassifyRejection(&sdktypes.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrMempoolIsFull.ABCICode(),
+	}))
+	require.Equal(t, tx.RejectionOther, tx.ClassifyRejection(&sdktypes.TxResponse{
+		Codespace: "liquidity",
+		Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+	}))
+}
+
+func TestTxHash(t *testing.T) {
+	txBytes := []byte("tx bytes")
+	require.Equal(t, fmt.Sprintf("%X", sha256.Sum256(txBytes)), tx.TxHash(txBytes))
+}
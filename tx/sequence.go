@@ -0,0 +1,244 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/b-harvest/cosmos-module-stress-test/client"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// expectedSequenceRegexp matches the raw log of a transaction rejected by the ante handler with a wrong sequence.
+var expectedSequenceRegexp = regexp.MustCompile(`account sequence mismatch, expected (\d+), got (\d+)`)
+
+// SequenceStats counts the broadcast results of the transactions of an account. The results are the ones the broadcast
+// mode reports, so a transaction broadcast in the async mode is counted as accepted even if the node rejects it.
+type SequenceStats struct {
+	Accepted      uint64
+	Rejected      uint64 // rejected before a block, e.g. by CheckTx
+	InCache       uint64 // rejected because the mempool cache of the node already holds the transaction
+	DeliverFailed uint64 // included in a block but failed in DeliverTx, which consumes the sequence
+	Resyncs       uint64
+}
+
+// accountSequence is the local sequence state of an account.
+type accountSequence struct {
+	accNum  uint64
+	next    uint64
+	pending map[uint64]struct{} // sequences handed out since the last resync
+
+	// committed sequence and block height observed by the last refresh
+	lastSeq    uint64
+	lastHeight int64
+
+	stats SequenceStats
+}
+
+// SequenceManager hands out account sequences to concurrent signers and keeps them in sync with the chain.
+// The sequence of an account is resynced when a broadcast response reports a rejected transaction, so that
+// a single rejected transaction does not make every later transaction of the account fail.
+type SequenceManager struct {
+	client *client.Client
+
+	mu       sync.Mutex
+	accounts map[string]*accountSequence
+}
+
+// NewSequenceManager returns new SequenceManager object.
+func NewSequenceManager(client *client.Client) *SequenceManager {
+	return &SequenceManager{
+		client:   client,
+		accounts: make(map[string]*accountSequence),
+	}
+}
+
+// Next returns the account number and the next sequence of the account.
+// The account is synced with the chain when it is used for the first time.
+func (m *SequenceManager) Next(ctx context.Context, address string) (uint64, uint64, error) {
+	m.mu.Lock()
+	acc, ok := m.accounts[address]
+	m.mu.Unlock()
+
+	if !ok {
+		if err := m.Refresh(ctx, address); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc = m.accounts[address]
+	seq := acc.next
+	acc.next++
+	acc.pending[seq] = struct{}{}
+
+	return acc.accNum, seq, nil
+}
+
+// Set sets the account number and the next sequence of the account without querying the chain.
+func (m *SequenceManager) Set(address string, accNum uint64, seq uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.accounts[address] = &accountSequence{
+		accNum:  accNum,
+		next:    seq,
+		pending: make(map[uint64]struct{}),
+		lastSeq: seq,
+	}
+}
+
+// Refresh queries the committed sequence of the account and resyncs the local sequence if it is out of date.
+// Broadcasting in async mode does not report rejected transactions, so the local sequence is also resynced when
+// the committed sequence has not advanced while blocks were committed since the previous refresh.
+func (m *SequenceManager) Refresh(ctx context.Context, address string) error {
+	account, err := m.client.GRPC.GetBaseAccountInfo(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to get account information: %s", err)
+	}
+
+	height, err := m.client.RPC.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seq := account.GetSequence()
+
+	acc, ok := m.accounts[address]
+	if !ok {
+		m.accounts[address] = &accountSequence{
+			accNum:     account.GetAccountNumber(),
+			next:       seq,
+			pending:    make(map[uint64]struct{}),
+			lastSeq:    seq,
+			lastHeight: height,
+		}
+		return nil
+	}
+
+	stalled := height > acc.lastHeight && seq == acc.lastSeq && acc.next > seq
+	if seq > acc.next || stalled {
+		acc.resync(seq)
+	}
+
+	acc.lastSeq = seq
+	acc.lastHeight = height
+
+	return nil
+}
+
+// Report records the broadcast response of the transaction signed with the given sequence.
+// It returns true when the sequence of the account is resynced because the transaction was rejected.
+func (m *SequenceManager) Report(address string, seq uint64, resp *sdktypes.TxResponse) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[address]
+	if !ok {
+		return false
+	}
+
+	_, current := acc.pending[seq]
+	delete(acc.pending, seq)
+
+	if resp.Code == 0 {
+		acc.stats.Accepted++
+		return false
+	}
+
+	// a transaction in the mempool cache was received by the node before, e.g. by a broadcast that timed out,
+	// so its sequence is in use, although it may have been dropped since
+	if ClassifyRejection(resp) == RejectionMempoolCache {
+		acc.stats.InCache++
+		return false
+	}
+
+	// a transaction included in a block consumed its sequence in the ante handler even if it failed in DeliverTx,
+	// as the block mode reports, so the sequence of the account is still in sync
+	if resp.Height > 0 {
+		acc.stats.DeliverFailed++
+		return false
+	}
+	acc.stats.Rejected++
+
+	// transactions handed out before the last resync are expected to be rejected
+	if !current {
+		return false
+	}
+
+	// a rejected transaction does not consume its sequence unless the node expects another one
+	expected, ok := ParseExpectedSequence(resp)
+	if !ok {
+		expected = seq
+	}
+	acc.resync(expected)
+
+	return true
+}
+
+// Release hands out the sequence again when the transaction signed with it is not broadcast.
+func (m *SequenceManager) Release(address string, seq uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[address]
+	if !ok {
+		return
+	}
+
+	if _, current := acc.pending[seq]; !current {
+		return
+	}
+
+	acc.next = seq
+	acc.pending = make(map[uint64]struct{})
+}
+
+// Stats returns the broadcast results of the account.
+func (m *SequenceManager) Stats(address string) SequenceStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[address]
+	if !ok {
+		return SequenceStats{}
+	}
+
+	return acc.stats
+}
+
+// resync sets the next sequence and forgets the sequences handed out so far.
+func (acc *accountSequence) resync(seq uint64) {
+	acc.next = seq
+	acc.pending = make(map[uint64]struct{})
+	acc.stats.Resyncs++
+}
+
+// ParseExpectedSequence returns the sequence expected by the node when the transaction was rejected with
+// the sdk ErrWrongSequence error.
+func ParseExpectedSequence(resp *sdktypes.TxResponse) (uint64, bool) {
+	if resp.Codespace != sdkerrors.RootCodespace || resp.Code != sdkerrors.ErrWrongSequence.ABCICode() {
+		return 0, false
+	}
+
+	matches := expectedSequenceRegexp.FindStringSubmatch(resp.RawLog)
+	if matches == nil {
+		return 0, false
+	}
+
+	expected, err := strconv.ParseUint(matches[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return expected, true
+}
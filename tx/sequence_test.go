@@ -0,0 +1,178 @@
+package tx_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func wrongSequenceResponse(expected string, got string) *sdktypes.TxResponse {
+	return &sdktypes.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+		RawLog:    "account sequence mismatch, expected " + expected + ", got " + got + ": incorrect account sequence",
+	}
+}
+
+func TestParseExpectedSequence(t *testing.T) {
+	expected, ok := tx.ParseExpectedSequence(wrongSequenceResponse("12", "15"))
+	require.True(t, ok)
+	require.Equal(t, uint64(12), expected)
+
+	_, ok = tx.ParseExpectedSequence(&sdktypes.TxResponse{})
+	require.False(t, ok)
+
+	_, ok = tx.ParseExpectedSequence(&sdktypes.TxResponse{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrInsufficientFee.ABCICode(),
+		RawLog:    "insufficient fees",
+	})
+	require.False(t, ok)
+}
+
+func TestSequenceManager(t *testing.T) {
+	ctx := context.Background()
+	address := "cosmos1zaavvzxez0elundtn32qnk9lkm8kmcszzsv80v"
+
+	seqs := tx.NewSequenceManager(c)
+	seqs.Set(address, 7, 10)
+
+	for i := uint64(0); i < 5; i++ {
+		accNum, seq, err := seqs.Next(ctx, address)
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), accNum)
+		require.Equal(t, 10+i, seq)
+	}
+
+	require.False(t, seqs.Report(address, 10, &sdktypes.TxResponse{}))
+	require.False(t, seqs.Report(address, 11, &sdktypes.TxResponse{}))
+
+	// the rejection of 12 makes the node expect 12 again
+	require.True(t, seqs.Report(address, 13, wrongSequenceResponse("12", "13")))
+
+	// transactions signed before the resync are ignored
+	require.False(t, seqs.Report(address, 14, wrongSequenceResponse("12", "14")))
+
+	_, seq, err := seqs.Next(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), seq)
+
+	// a rejection for another reason does not consume the sequence
+	require.True(t, seqs.Report(address, 12, &sdktypes.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrInsufficientFee.ABCICode()}))
+
+	_, seq, err = seqs.Next(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), seq)
+
+	// a transaction in the mempool cache was received by the node, so its sequence is in use, but it is not accepted
+	require.False(t, seqs.Report(address, 12, &sdktypes.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrTxInMempoolCache.ABCICode()}))
+
+	_, seq, err = seqs.Next(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(13), seq)
+
+	stats := seqs.Stats(address)
+	require.Equal(t, uint64(2), stats.Accepted)
+	require.Equal(t, uint64(3), stats.Rejected)
+	require.Equal(t, uint64(1), stats.InCache)
+	require.Equal(t, uint64(2), stats.Resyncs)
+}
+
+func TestSequenceManagerBlockMode(t *testing.T) {
+	ctx := context.Background()
+	address := "cosmos1zaavvzxez0elundtn32qnk9lkm8kmcszzsv80v"
+
+	seqs := tx.NewSequenceManager(c)
+	seqs.Set(address, 7, 10)
+
+	for i := 0; i < 3; i++ {
+		_, _, err := seqs.Next(ctx, address)
+		require.NoError(t, err)
+	}
+
+	require.False(t, seqs.Report(address, 10, &sdktypes.TxResponse{Height: 5}))
+
+	// a transaction that failed in DeliverTx consumed its sequence, so the account is not resynced
+	require.False(t, seqs.Report(address, 11, &sdktypes.TxResponse{Height: 5, Codespace: "liquidity", Code: 29}))
+
+	// a transaction rejected by CheckTx in the block mode has no height and does not consume its sequence
+	require.True(t, seqs.Report(address, 12, &sdktypes.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrInsufficientFee.ABCICode()}))
+
+	_, seq, err := seqs.Next(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), seq)
+
+	stats := seqs.Stats(address)
+	require.Equal(t, uint64(1), stats.Accepted)
+	require.Equal(t, uint64(1), stats.DeliverFailed)
+	require.Equal(t, uint64(1), stats.Rejected)
+	require.Equal(t, uint64(1), stats.Resyncs)
+}
+
+func TestSequenceManagerRelease(t *testing.T) {
+	ctx := context.Background()
+	address := "cosmos1zaavvzxez0elundtn32qnk9lkm8kmcszzsv80v"
+
+	seqs := tx.NewSequenceManager(c)
+	seqs.Set(address, 7, 10)
+
+	_, seq, err := seqs.Next(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), seq)
+
+	// a released sequence is handed out again
+	seqs.Release(address, seq)
+
+	_, seq, err = seqs.Next(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), seq)
+
+	// a sequence handed out before a resync is not released
+	require.True(t, seqs.Report(address, 10, wrongSequenceResponse("8", "10")))
+	seqs.Release(address, 10)
+
+	_, seq, err = seqs.Next(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), seq)
+
+	require.Equal(t, uint64(1), seqs.Stats(address).Resyncs)
+}
+
+func TestSequenceManagerConcurrency(t *testing.T) {
+	ctx := context.Background()
+	address := "cosmos1zaavvzxez0elundtn32qnk9lkm8kmcszzsv80v"
+
+	seqs := tx.NewSequenceManager(c)
+	seqs.Set(address, 0, 0)
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		seen = make(map[uint64]bool)
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, seq, err := seqs.Next(ctx, address)
+				require.NoError(t, err)
+
+				mu.Lock()
+				require.False(t, seen[seq])
+				seen[seq] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, seen, 1000)
+}
@@ -0,0 +1,85 @@
+package tx
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// SignMode selects the sign mode of the transactions signed by a Transaction.
+type SignMode string
+
+const (
+	// SignModeDefault signs in the default sign mode of the tx config, which is SIGN_MODE_DIRECT.
+	SignModeDefault SignMode = ""
+	// SignModeDirect signs in SIGN_MODE_DIRECT.
+	SignModeDirect SignMode = "direct"
+	// SignModeAminoJSON signs in SIGN_MODE_LEGACY_AMINO_JSON.
+	SignModeAminoJSON SignMode = "amino-json"
+	// SignModeRandom signs every transaction in either SIGN_MODE_DIRECT or SIGN_MODE_LEGACY_AMINO_JSON at random.
+	SignModeRandom SignMode = "random"
+)
+
+// ParseSignMode parses the sign mode of the config.
+func ParseSignMode(mode string) (SignMode, error) {
+	switch SignMode(mode) {
+	case SignModeDefault, SignModeDirect, SignModeAminoJSON, SignModeRandom:
+		return SignMode(mode), nil
+	default:
+		return "", fmt.Errorf("unsupported sign mode %s; must be one of %s, %s or %s", mode, SignModeDirect, SignModeAminoJSON, SignModeRandom)
+	}
+}
+
+// String returns the sign mode as it is written in the config.
+func (m SignMode) String() string {
+	if m == SignModeDefault {
+		return "default"
+	}
+	return string(m)
+}
+
+// signMode picks the sign mode of the next transaction. The transaction is counted by countSignMode once it is signed.
+func (t *Transaction) signMode() signing.SignMode {
+	var mode signing.SignMode
+	switch t.SignMode {
+	case SignModeDirect:
+		mode = signing.SignMode_SIGN_MODE_DIRECT
+	case SignModeAminoJSON:
+		mode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+	case SignModeRandom:
+		if rand.Intn(2) == 0 {
+			mode = signing.SignMode_SIGN_MODE_DIRECT
+		} else {
+			mode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+		}
+	default:
+		mode = t.Client.CliCtx.TxConfig.SignModeHandler().DefaultMode()
+	}
+
+	return mode
+}
+
+// countSignMode counts a transaction signed in the sign mode.
+func (t *Transaction) countSignMode(mode signing.SignMode) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.signModeCounts == nil {
+		t.signModeCounts = make(map[signing.SignMode]uint64)
+	}
+	t.signModeCounts[mode]++
+}
+
+// SignModeCounts returns the number of transactions signed in each sign mode.
+func (t *Transaction) SignModeCounts() map[signing.SignMode]uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[signing.SignMode]uint64, len(t.signModeCounts))
+	for mode, count := range t.signModeCounts {
+		counts[mode] = count
+	}
+
+	return counts
+}
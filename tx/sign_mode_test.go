@@ -0,0 +1,80 @@
+package tx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+func TestParseSignMode(t *testing.T) {
+	for _, mode := range []string{"", "direct", "amino-json", "random"} {
+		signMode, err := tx.ParseSignMode(mode)
+		require.NoError(t, err)
+		require.Equal(t, tx.SignMode(mode), signMode)
+	}
+
+	_, err := tx.ParseSignMode("textual")
+	require.Error(t, err)
+}
+
+func TestSignWithSignMode(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 2)
+	require.NoError(t, err)
+	acc := accounts[0]
+
+	msg, err := tx.MsgSend(acc.Address, accounts[1].Address, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 1)))
+	require.NoError(t, err)
+
+	signerData := authsigning.SignerData{ChainID: "localnet", AccountNumber: 7, Sequence: 5}
+
+	for _, tc := range []struct {
+		signMode tx.SignMode
+		expected signing.SignMode
+	}{
+		{tx.SignModeDefault, signing.SignMode_SIGN_MODE_DIRECT},
+		{tx.SignModeDirect, signing.SignMode_SIGN_MODE_DIRECT},
+		{tx.SignModeAminoJSON, signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON},
+	} {
+		transaction := tx.NewTransaction(c, "localnet", 200000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "")
+		transaction.SignMode = tc.signMode
+
+		txByte, err := transaction.Sign(context.Background(), 5, 7, acc.Signer, msg)
+		require.NoError(t, err)
+
+		decoded, err := c.CliCtx.TxConfig.TxDecoder()(txByte)
+		require.NoError(t, err)
+
+		sigTx := decoded.(authsigning.SigVerifiableTx)
+		sigs, err := sigTx.GetSignaturesV2()
+		require.NoError(t, err)
+		require.Len(t, sigs, 1)
+		require.Equal(t, tc.expected, sigs[0].Data.(*signing.SingleSignatureData).SignMode)
+
+		err = authsigning.VerifySignature(sigs[0].PubKey, signerData, sigs[0].Data, c.CliCtx.TxConfig.SignModeHandler(), sigTx)
+		require.NoError(t, err)
+
+		require.Equal(t, map[signing.SignMode]uint64{tc.expected: 1}, transaction.SignModeCounts())
+	}
+
+	transaction := tx.NewTransaction(c, "localnet", 200000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "")
+	transaction.SignMode = tx.SignModeRandom
+
+	for i := 0; i < 100; i++ {
+		_, err := transaction.Sign(context.Background(), 5, 7, acc.Signer, msg)
+		require.NoError(t, err)
+	}
+
+	counts := transaction.SignModeCounts()
+	require.Len(t, counts, 2)
+	require.Equal(t, uint64(100), counts[signing.SignMode_SIGN_MODE_DIRECT]+counts[signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON])
+}
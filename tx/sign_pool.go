@@ -0,0 +1,64 @@
+package tx
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// SignJob is a transaction of the messages to be signed by the signer with the account number and the sequence.
+type SignJob struct {
+	AccNum uint64
+	Seq    uint64
+	Signer wallet.Signer
+	Msgs   []sdktypes.Msg
+}
+
+// SignAll signs the jobs with SignWorkers concurrent goroutines and returns the signed transactions in the order of
+// the jobs, so that the transactions of an account are broadcast in the order of their sequences.
+// The signers of in-memory keys sign in parallel, while keyring-backed signers sign one at a time.
+// It returns the error of the first job that failed to be signed.
+func (t *Transaction) SignAll(ctx context.Context, jobs []SignJob) ([][]byte, error) {
+	workers := t.SignWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	txs := make([][]byte, len(jobs))
+	errs := make([]error, len(jobs))
+
+	indexes := make(chan int, len(jobs))
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				job := jobs[i]
+				txs[i], errs[i] = t.Sign(ctx, job.Seq, job.AccNum, job.Signer, job.Msgs...)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return txs, nil
+}
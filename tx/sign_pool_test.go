@@ -0,0 +1,128 @@
+package tx_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/tx"
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// failingSigner is a signer that fails to sign.
+type failingSigner struct {
+	wallet.Signer
+}
+
+func (s failingSigner) Sign(msg []byte) ([]byte, error) {
+	return nil, fmt.Errorf("failed to sign")
+}
+
+// signJobs returns num jobs of the accounts in turn, each with msgNum send messages and consecutive sequences.
+func signJobs(t testing.TB, accounts []wallet.Account, num int, msgNum int) []tx.SignJob {
+	jobs := make([]tx.SignJob, 0, num)
+	for i := 0; i < num; i++ {
+		acc := accounts[i%len(accounts)]
+
+		msgs := make([]sdktypes.Msg, 0, msgNum)
+		for j := 0; j < msgNum; j++ {
+			msg, err := tx.MsgSend(acc.Address, acc.Address, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", int64(j+1))))
+			require.NoError(t, err)
+			msgs = append(msgs, msg)
+		}
+
+		jobs = append(jobs, tx.SignJob{
+			AccNum: uint64(i % len(accounts)),
+			Seq:    uint64(i / len(accounts)),
+			Signer: acc.Signer,
+			Msgs:   msgs,
+		})
+	}
+
+	return jobs
+}
+
+func TestSignAll(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 3)
+	require.NoError(t, err)
+
+	jobs := signJobs(t, accounts, 30, 2)
+
+	transaction := tx.NewTransaction(c, "localnet", 200000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "")
+	transaction.SignWorkers = 4
+
+	txs, err := transaction.SignAll(context.Background(), jobs)
+	require.NoError(t, err)
+	require.Len(t, txs, len(jobs))
+
+	for i, job := range jobs {
+		// signatures are deterministic, so the transactions equal the ones signed one by one
+		expected, err := transaction.Sign(context.Background(), job.Seq, job.AccNum, job.Signer, job.Msgs...)
+		require.NoError(t, err)
+		require.Equal(t, expected, txs[i])
+
+		decoded, err := c.CliCtx.TxConfig.TxDecoder()(txs[i])
+		require.NoError(t, err)
+
+		sigs, err := decoded.(authsigning.SigVerifiableTx).GetSignaturesV2()
+		require.NoError(t, err)
+		require.Equal(t, job.Seq, sigs[0].Sequence)
+	}
+
+	// a job that fails to be signed fails every job
+	jobs[7].Signer = failingSigner{jobs[7].Signer}
+	_, err = transaction.SignAll(context.Background(), jobs)
+	require.Error(t, err)
+}
+
+func BenchmarkSign(b *testing.B) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 1)
+	require.NoError(b, err)
+
+	transaction := tx.NewTransaction(c, "localnet", 200000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "")
+
+	for _, msgNum := range []int{1, 10, 100} {
+		job := signJobs(b, accounts, 1, msgNum)[0]
+
+		b.Run(fmt.Sprintf("msgs-%d", msgNum), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := transaction.Sign(context.Background(), uint64(i), job.AccNum, job.Signer, job.Msgs...)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSignAll(b *testing.B) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 10)
+	require.NoError(b, err)
+
+	transaction := tx.NewTransaction(c, "localnet", 200000, sdktypes.NewCoins(sdktypes.NewInt64Coin("stake", 10)), "")
+
+	for _, msgNum := range []int{1, 10, 100} {
+		jobs := signJobs(b, accounts, 100, msgNum)
+
+		// every iteration signs 100 transactions with as many goroutines as CPUs
+		b.Run(fmt.Sprintf("msgs-%d", msgNum), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := transaction.SignAll(context.Background(), jobs)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	bip39 "github.com/cosmos/go-bip39"
+)
+
+// AccountKey is an account entry of an accounts file.
+// The mnemonic is only known for the accounts generated with a new mnemonic.
+type AccountKey struct {
+	Address  string `json:"address"`
+	PrivKey  string `json:"priv_key"`
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
+// GenerateAccountKeys generates count accounts, each from a new mnemonic.
+func GenerateAccountKeys(count uint32) ([]AccountKey, error) {
+	keys := make([]AccountKey, 0, count)
+	for i := uint32(0); i < count; i++ {
+		entropy, err := bip39.NewEntropy(256)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate entropy: %s", err)
+		}
+
+		mnemonic, err := bip39.NewMnemonic(entropy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate mnemonic: %s", err)
+		}
+
+		accAddr, privKey, err := RecoverAccountFromMnemonic(mnemonic, "")
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, AccountKey{
+			Address:  accAddr,
+			PrivKey:  hex.EncodeToString(privKey.Bytes()),
+			Mnemonic: mnemonic,
+		})
+	}
+
+	return keys, nil
+}
+
+// NewAccountKeys returns the account entries of accounts whose signers are raw private keys.
+func NewAccountKeys(accounts []Account) ([]AccountKey, error) {
+	keys := make([]AccountKey, 0, len(accounts))
+	for _, acc := range accounts {
+		privKey, ok := acc.Signer.(*secp256k1.PrivKey)
+		if !ok {
+			return nil, fmt.Errorf("private key of %s cannot be exported", acc.Address)
+		}
+
+		keys = append(keys, AccountKey{
+			Address: acc.Address,
+			PrivKey: hex.EncodeToString(privKey.Bytes()),
+		})
+	}
+
+	return keys, nil
+}
+
+// WriteAccountsFile writes the account entries to a JSON accounts file.
+func WriteAccountsFile(path string, keys []AccountKey) error {
+	bz, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal accounts: %s", err)
+	}
+
+	err = ioutil.WriteFile(path, bz, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write accounts file: %s", err)
+	}
+
+	return nil
+}
+
+// ReadAccountsFile reads the accounts of a JSON accounts file.
+// The addresses are encoded again with the bech32 prefix of the Cosmos SDK config.
+func ReadAccountsFile(path string) ([]Account, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accounts file: %s", err)
+	}
+
+	var keys []AccountKey
+	err = json.Unmarshal(bz, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal accounts file: %s", err)
+	}
+
+	accounts := make([]Account, 0, len(keys))
+	for _, key := range keys {
+		privKeyBytes, err := hex.DecodeString(key.PrivKey)
+		if err != nil || len(privKeyBytes) != secp256k1.PrivKeySize {
+			return nil, fmt.Errorf("invalid private key of %s", key.Address)
+		}
+
+		privKey := &secp256k1.PrivKey{Key: privKeyBytes}
+
+		accAddr, err := bech32.ConvertAndEncode(sdktypes.GetConfig().GetBech32AccountAddrPrefix(), privKey.PubKey().Address())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert and encode address: %s", err)
+		}
+
+		accounts = append(accounts, Account{
+			Address: accAddr,
+			Signer:  privKey,
+		})
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,43 @@
+package wallet_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+)
+
+func TestAccountsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.json")
+
+	generated, err := wallet.GenerateAccountKeys(3)
+	require.NoError(t, err)
+
+	for _, key := range generated {
+		accAddr, _, err := wallet.RecoverAccountFromMnemonic(key.Mnemonic, "")
+		require.NoError(t, err)
+		require.Equal(t, accAddr, key.Address)
+	}
+
+	err = wallet.WriteAccountsFile(path, generated)
+	require.NoError(t, err)
+
+	accounts, err := wallet.ReadAccountsFile(path)
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+
+	for i, acc := range accounts {
+		require.Equal(t, generated[i].Address, acc.Address)
+	}
+
+	// derived accounts are written without mnemonic
+	keys, err := wallet.NewAccountKeys(accounts)
+	require.NoError(t, err)
+	require.Equal(t, generated[0].PrivKey, keys[0].PrivKey)
+	require.Empty(t, keys[0].Mnemonic)
+
+	_, err = wallet.ReadAccountsFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+const (
+	// KeyringAppName is the application name of the keyring that stores the keys of the tester.
+	KeyringAppName = "tester"
+
+	// KeyringPassphraseEnv is the environment variable that supplies the passphrase of the file keyring.
+	// The keyring prompts for the passphrase on the terminal when the standard input is a terminal,
+	// so the variable is only read when the tester runs without one, e.g. in a script.
+	KeyringPassphraseEnv = "TESTER_KEYRING_PASSPHRASE"
+)
+
+// keyringMu serializes the signing with the keys of keyrings. A keyring is not safe for concurrent use:
+// the file backend reads and decrypts the key file on every signing, and the keys of the accounts share a keyring.
+var keyringMu sync.Mutex
+
+// KeyringSigner is a signer whose private key is stored in a Cosmos SDK keyring.
+// It signs one message at a time with the other keyring signers, even when it is used concurrently.
+type KeyringSigner struct {
+	keyring keyring.Keyring
+	name    string
+	pubKey  cryptotypes.PubKey
+}
+
+// PubKey returns the public key of the keyring key.
+func (s *KeyringSigner) PubKey() cryptotypes.PubKey {
+	return s.pubKey
+}
+
+// Sign signs the message with the keyring key.
+func (s *KeyringSigner) Sign(msg []byte) ([]byte, error) {
+	keyringMu.Lock()
+	defer keyringMu.Unlock()
+
+	sig, _, err := s.keyring.Sign(s.name, msg)
+	return sig, err
+}
+
+// NewKeyring opens a Cosmos SDK keyring of the file or test backend in the given directory.
+func NewKeyring(backend string, dir string) (keyring.Keyring, error) {
+	switch backend {
+	case keyring.BackendFile, keyring.BackendTest:
+	default:
+		return nil, fmt.Errorf("unsupported keyring backend %s; must be either %s or %s", backend, keyring.BackendFile, keyring.BackendTest)
+	}
+
+	var input io.Reader = os.Stdin
+
+	passphrase, ok := os.LookupEnv(KeyringPassphraseEnv)
+	if ok {
+		// the passphrase is repeated for the confirmation prompt of a new keyring
+		input = strings.NewReader(passphrase + "\n" + passphrase + "\n")
+	}
+
+	kr, err := keyring.New(KeyringAppName, backend, dir, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keyring: %s", err)
+	}
+
+	return kr, nil
+}
+
+// RecoverAccountFromKeyring loads the key of the given name from the keyring and returns its account.
+// The file keyring is unlocked at this point, so the passphrase is supplied only once.
+func RecoverAccountFromKeyring(kr keyring.Keyring, name string) (Account, error) {
+	info, err := kr.Key(name)
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to load key %s: %s", name, err)
+	}
+
+	accAddr, err := bech32.ConvertAndEncode(sdktypes.GetConfig().GetBech32AccountAddrPrefix(), info.GetAddress())
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to convert and encode address: %s", err)
+	}
+
+	return Account{
+		Address: accAddr,
+		Signer: &KeyringSigner{
+			keyring: kr,
+			name:    name,
+			pubKey:  info.GetPubKey(),
+		},
+	}, nil
+}
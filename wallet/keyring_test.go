@@ -0,0 +1,66 @@
+package wallet_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+)
+
+func TestRecoverAccountFromKeyring(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accAddr, privKey, err := wallet.RecoverAccountFromMnemonic(mnemonic, "")
+	require.NoError(t, err)
+
+	os.Setenv(wallet.KeyringPassphraseEnv, "12345678")
+	defer os.Unsetenv(wallet.KeyringPassphraseEnv)
+
+	for _, backend := range []string{keyring.BackendTest, keyring.BackendFile} {
+		t.Run(backend, func(t *testing.T) {
+			kr, err := wallet.NewKeyring(backend, t.TempDir())
+			require.NoError(t, err)
+
+			_, err = kr.NewAccount("master", mnemonic, "", sdktypes.GetConfig().GetFullFundraiserPath(), hd.Secp256k1)
+			require.NoError(t, err)
+
+			acc, err := wallet.RecoverAccountFromKeyring(kr, "master")
+			require.NoError(t, err)
+			require.Equal(t, accAddr, acc.Address)
+			require.Equal(t, privKey.PubKey(), acc.Signer.PubKey())
+
+			msg := []byte("stress test")
+			sig, err := acc.Signer.Sign(msg)
+			require.NoError(t, err)
+			require.True(t, privKey.PubKey().VerifySignature(msg, sig))
+
+			// the keyring is shared by the signers of a concurrent signing
+			var wg sync.WaitGroup
+			sigs := make([][]byte, 8)
+			for i := range sigs {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					sigs[i], _ = acc.Signer.Sign(msg)
+				}(i)
+			}
+			wg.Wait()
+			for _, sig := range sigs {
+				require.True(t, privKey.PubKey().VerifySignature(msg, sig))
+			}
+
+			_, err = wallet.RecoverAccountFromKeyring(kr, "unknown")
+			require.Error(t, err)
+		})
+	}
+
+	_, err = wallet.NewKeyring("os", t.TempDir())
+	require.Error(t, err)
+}
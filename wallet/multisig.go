@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"fmt"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// MultisigSigner is the signer of a k-of-n legacy amino multisig account.
+// Its multisignature is assembled by the tx package from the signatures of the first k keys.
+type MultisigSigner struct {
+	pubKey  *kmultisig.LegacyAminoPubKey
+	signers []Signer
+}
+
+// PubKey returns the multisig public key.
+func (s *MultisigSigner) PubKey() cryptotypes.PubKey {
+	return s.pubKey
+}
+
+// Sign always fails because a multisignature is not a single signature of the message.
+func (s *MultisigSigner) Sign(msg []byte) ([]byte, error) {
+	return nil, fmt.Errorf("multisig signer cannot sign bytes; sign the transaction with the keys of the multisig instead")
+}
+
+// Signers returns the signers of the keys of the multisig.
+func (s *MultisigSigner) Signers() []Signer {
+	return s.signers
+}
+
+// Threshold returns the number of signatures required by the multisig.
+func (s *MultisigSigner) Threshold() int {
+	return int(s.pubKey.GetThreshold())
+}
+
+// NewMultisigAccount builds a threshold-of-n multisig account from the n signers.
+func NewMultisigAccount(threshold int, signers []Signer) (Account, error) {
+	if threshold <= 0 || threshold > len(signers) {
+		return Account{}, fmt.Errorf("multisig threshold must be between 1 and the number of keys %d: %d", len(signers), threshold)
+	}
+
+	pubKeys := make([]cryptotypes.PubKey, 0, len(signers))
+	for _, signer := range signers {
+		pubKeys = append(pubKeys, signer.PubKey())
+	}
+
+	pubKey := kmultisig.NewLegacyAminoPubKey(threshold, pubKeys)
+
+	accAddr, err := bech32.ConvertAndEncode(sdktypes.GetConfig().GetBech32AccountAddrPrefix(), pubKey.Address())
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to convert and encode address: %s", err)
+	}
+
+	return Account{
+		Address: accAddr,
+		Signer: &MultisigSigner{
+			pubKey:  pubKey,
+			signers: signers,
+		},
+	}, nil
+}
+
+// NewMultisigAccounts groups every consecutive numKeys accounts into a threshold-of-numKeys multisig account.
+func NewMultisigAccounts(accounts []Account, threshold int, numKeys int) ([]Account, error) {
+	if numKeys <= 0 || len(accounts)%numKeys != 0 {
+		return nil, fmt.Errorf("%d accounts cannot be grouped into multisig accounts of %d keys", len(accounts), numKeys)
+	}
+
+	multisigs := make([]Account, 0, len(accounts)/numKeys)
+	for i := 0; i < len(accounts); i += numKeys {
+		signers := make([]Signer, 0, numKeys)
+		for _, acc := range accounts[i : i+numKeys] {
+			signers = append(signers, acc.Signer)
+		}
+
+		multisig, err := NewMultisigAccount(threshold, signers)
+		if err != nil {
+			return nil, err
+		}
+		multisigs = append(multisigs, multisig)
+	}
+
+	return multisigs, nil
+}
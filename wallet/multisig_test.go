@@ -0,0 +1,40 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/require"
+
+	"github.com/b-harvest/cosmos-module-stress-test/wallet"
+)
+
+func TestNewMultisigAccounts(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 6)
+	require.NoError(t, err)
+
+	multisigs, err := wallet.NewMultisigAccounts(accounts, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, multisigs, 2)
+	require.NotEqual(t, multisigs[0].Address, multisigs[1].Address)
+
+	signer := multisigs[1].Signer.(*wallet.MultisigSigner)
+	require.Equal(t, 2, signer.Threshold())
+	require.Len(t, signer.Signers(), 3)
+	require.Equal(t, accounts[3].Signer.PubKey(), signer.Signers()[0].PubKey())
+
+	_, err = signer.Sign([]byte("stress test"))
+	require.Error(t, err)
+
+	// the multisig address is deterministic
+	again, err := wallet.NewMultisigAccounts(accounts[:3], 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, multisigs[0].Address, again[0].Address)
+
+	_, err = wallet.NewMultisigAccounts(accounts, 2, 4)
+	require.Error(t, err)
+
+	_, err = wallet.NewMultisigAccounts(accounts, 4, 3)
+	require.Error(t, err)
+}
@@ -5,27 +5,78 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 	bip39 "github.com/cosmos/go-bip39"
 )
 
+// Signer signs bytes on behalf of an account. Both a raw private key and a key stored in a keyring are signers.
+type Signer interface {
+	PubKey() cryptotypes.PubKey
+	Sign(msg []byte) ([]byte, error)
+}
+
+// Account is an account with its bech32 address and the signer of its transactions.
+type Account struct {
+	Address string
+	Signer  Signer
+}
+
 // RecoverAccountFromMnemonic recovers private key from mnemonic and return account address after bech32 encoding.
 func RecoverAccountFromMnemonic(mnemonic string, password string) (string, *secp256k1.PrivKey, error) {
-	seed := bip39.NewSeed(mnemonic, password)
-	masterKey, ch := hd.ComputeMastersFromSeed(seed)
-	priv, err := hd.DerivePrivateKeyForPath(masterKey, ch, sdktypes.GetConfig().GetFullFundraiserPath()) // "44'/118'/0'/0/0"
+	params, err := hd.NewParamsFromPath(sdktypes.GetConfig().GetFullFundraiserPath()) // "44'/118'/0'/0/0"
 	if err != nil {
-		return "", &secp256k1.PrivKey{}, fmt.Errorf("failed to derive private key for path: %s", err)
+		return "", &secp256k1.PrivKey{}, fmt.Errorf("failed to parse hd path: %s", err)
 	}
 
-	privKey := &secp256k1.PrivKey{Key: priv}
-	pubKey := privKey.PubKey()
+	accounts, err := RecoverAccountsFromMnemonic(mnemonic, password, params.Account, params.AddressIndex, 1)
+	if err != nil {
+		return "", &secp256k1.PrivKey{}, err
+	}
 
-	accAddr, err := bech32.ConvertAndEncode(sdktypes.GetConfig().GetBech32AccountAddrPrefix(), pubKey.Address())
+	return accounts[0].Address, accounts[0].Signer.(*secp256k1.PrivKey), nil
+}
+
+// RecoverAccountsFromMnemonic derives count accounts from mnemonic starting at the given HD account and address index.
+// The accounts are derived from the HD path of the Cosmos SDK config with its account and address index replaced,
+// i.e. from "44'/118'/account'/0/index" to "44'/118'/account'/0/index+count-1" for the default path.
+func RecoverAccountsFromMnemonic(mnemonic string, password string, account uint32, index uint32, count uint32) ([]Account, error) {
+	if count == 0 {
+		return nil, fmt.Errorf("number of accounts must be positive")
+	}
+
+	params, err := hd.NewParamsFromPath(sdktypes.GetConfig().GetFullFundraiserPath())
 	if err != nil {
-		return "", &secp256k1.PrivKey{}, fmt.Errorf("failed to convert and encode address: %s", err)
+		return nil, fmt.Errorf("failed to parse hd path: %s", err)
+	}
+	params.Account = account
+
+	seed := bip39.NewSeed(mnemonic, password)
+	masterKey, ch := hd.ComputeMastersFromSeed(seed)
+
+	accounts := make([]Account, 0, count)
+	for i := uint32(0); i < count; i++ {
+		params.AddressIndex = index + i
+		path := params.String()
+
+		priv, err := hd.DerivePrivateKeyForPath(masterKey, ch, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive private key for path %s: %s", path, err)
+		}
+
+		privKey := &secp256k1.PrivKey{Key: priv}
+
+		accAddr, err := bech32.ConvertAndEncode(sdktypes.GetConfig().GetBech32AccountAddrPrefix(), privKey.PubKey().Address())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert and encode address: %s", err)
+		}
+
+		accounts = append(accounts, Account{
+			Address: accAddr,
+			Signer:  privKey,
+		})
 	}
 
-	return accAddr, privKey, nil
+	return accounts, nil
 }
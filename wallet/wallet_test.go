@@ -55,3 +55,37 @@ func TestNewMnemonic(t *testing.T) {
 		t.Log(mnemonic, accAddr)
 	}
 }
+
+func TestRecoverAccountsFromMnemonic(t *testing.T) {
+	mnemonic := "guard cream sadness conduct invite crumble clock pudding hole grit liar hotel maid produce squeeze return argue turtle know drive eight casino maze host"
+
+	accAddr, privKey, err := wallet.RecoverAccountFromMnemonic(mnemonic, "")
+	require.NoError(t, err)
+
+	accounts, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, accounts, 10)
+	require.Equal(t, accAddr, accounts[0].Address)
+	require.Equal(t, privKey.PubKey(), accounts[0].Signer.PubKey())
+
+	seen := make(map[string]bool)
+	for _, acc := range accounts {
+		require.False(t, seen[acc.Address])
+		seen[acc.Address] = true
+	}
+
+	// an address index range continues where the previous range stopped
+	shifted, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 5, 5)
+	require.NoError(t, err)
+	for i, acc := range shifted {
+		require.Equal(t, accounts[5+i].Address, acc.Address)
+	}
+
+	// a different HD account derives different addresses
+	other, err := wallet.RecoverAccountsFromMnemonic(mnemonic, "", 1, 0, 1)
+	require.NoError(t, err)
+	require.False(t, seen[other[0].Address])
+
+	_, err = wallet.RecoverAccountsFromMnemonic(mnemonic, "", 0, 0, 0)
+	require.Error(t, err)
+}